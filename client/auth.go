@@ -0,0 +1,237 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	apiKeyAuthenticatePath = "/bim/apikey/authenticate"
+	loginPath              = "/bim/login"
+
+	// DefaultIamId is the built-in identity manager used for username and password login
+	DefaultIamId = "bim"
+)
+
+// TokenSource supplies the bearer token sent with every request
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+	// Invalidate is called when Immuta rejects a token with a 401. It returns true when the token has
+	// changed since, in which case the request is retried once with the new token.
+	Invalidate(ctx context.Context, token string) (bool, error)
+}
+
+// WithTokenSource authenticates requests with tokens from the given source instead of a static API token
+func WithTokenSource(tokens TokenSource) Option {
+	return func(c *ImmutaClient) {
+		c.tokens = tokens
+	}
+}
+
+// WithAPIKey exchanges an API key for a session token, which is exchanged again once it expires
+func WithAPIKey(apiKey string) Option {
+	return func(c *ImmutaClient) {
+		c.tokens = newCachingTokenSource(func(ctx context.Context) (string, error) {
+			return c.exchangeToken(ctx, apiKeyAuthenticatePath, apiKeyAuthenticateRequest{ApiKey: apiKey})
+		})
+	}
+}
+
+// WithPasswordLogin logs in with a username and password for a session token, logging in again once it expires
+func WithPasswordLogin(username, password, iamId string) Option {
+	if iamId == "" {
+		iamId = DefaultIamId
+	}
+	return func(c *ImmutaClient) {
+		c.tokens = newCachingTokenSource(func(ctx context.Context) (string, error) {
+			return c.exchangeToken(ctx, loginPath, loginRequest{Username: username, Password: password, IamId: iamId})
+		})
+	}
+}
+
+// exchangeToken posts credentials to an authentication endpoint without sending a bearer token
+func (c *ImmutaClient) exchangeToken(ctx context.Context, path string, credentials interface{}) (string, error) {
+	output := tokenResponse{}
+	if err := c.doRequestWithTokens(ctx, nil, http.MethodPost, path, "", nil, credentials, &output, true); err != nil {
+		return "", fmt.Errorf("could not authenticate with Immuta: %w", err)
+	}
+	if output.Token == "" {
+		return "", errors.New("could not authenticate with Immuta: no token in the response")
+	}
+	return output.Token, nil
+}
+
+type staticTokenSource string
+
+// StaticToken always returns the same token, e.g. an API token from the environment
+func StaticToken(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+func (s staticTokenSource) Token(_ context.Context) (string, error) {
+	return string(s), nil
+}
+
+func (s staticTokenSource) Invalidate(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
+
+// FileToken reads the token from a file, which is read again when the token is rejected
+// so an external process can keep it up to date
+func FileToken(path string) TokenSource {
+	return newCachingTokenSource(func(_ context.Context) (string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("could not read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", path)
+		}
+		return token, nil
+	})
+}
+
+// CommandToken runs an external credential command and uses its output as the token,
+// the command is run again when the token is rejected
+func CommandToken(command []string) TokenSource {
+	return newCachingTokenSource(func(ctx context.Context) (string, error) {
+		if len(command) == 0 {
+			return "", errors.New("token command is empty")
+		}
+
+		stderr := bytes.Buffer{}
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stderr = &stderr
+
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("token command %s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+		}
+
+		token := strings.TrimSpace(string(output))
+		if token == "" {
+			return "", fmt.Errorf("token command %s printed no token", command[0])
+		}
+		return token, nil
+	})
+}
+
+// cachingTokenSource fetches a token on first use and keeps it until it is invalidated. Concurrent callers share
+// a single fetch, the lock is only held to hand it out so a slow exchange does not block requests that already
+// have a token.
+type cachingTokenSource struct {
+	mu       sync.Mutex
+	token    string
+	inflight *tokenFetch
+	fetch    func(ctx context.Context) (string, error)
+}
+
+// tokenFetch is a fetch in progress, done is closed once token or err is set
+type tokenFetch struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func newCachingTokenSource(fetch func(ctx context.Context) (string, error)) *cachingTokenSource {
+	return &cachingTokenSource{fetch: fetch}
+}
+
+func (s *cachingTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	if token != "" {
+		return token, nil
+	}
+	return s.refresh(ctx)
+}
+
+func (s *cachingTokenSource) Invalidate(ctx context.Context, token string) (bool, error) {
+	s.mu.Lock()
+	current := s.token
+	if current == token {
+		s.token = ""
+	}
+	s.mu.Unlock()
+
+	// a concurrent request may already have replaced the rejected token
+	if current != token && current != "" {
+		return true, nil
+	}
+
+	refreshed, err := s.refresh(ctx)
+	if err != nil {
+		return false, err
+	}
+	return refreshed != token, nil
+}
+
+// refresh fetches a new token, joining a fetch already in progress. Each caller only waits as long as its own
+// context allows, and a fetch abandoned by the caller that started it is started again for the others.
+func (s *cachingTokenSource) refresh(ctx context.Context) (string, error) {
+	for {
+		s.mu.Lock()
+		if s.token != "" {
+			token := s.token
+			s.mu.Unlock()
+			return token, nil
+		}
+
+		fetch := s.inflight
+		if fetch == nil {
+			fetch = &tokenFetch{done: make(chan struct{})}
+			s.inflight = fetch
+			s.mu.Unlock()
+
+			fetch.token, fetch.err = s.fetch(ctx)
+
+			s.mu.Lock()
+			if fetch.err == nil {
+				s.token = fetch.token
+			}
+			s.inflight = nil
+			s.mu.Unlock()
+			close(fetch.done)
+			return fetch.token, fetch.err
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-fetch.done:
+		}
+
+		if fetch.err == nil {
+			return fetch.token, nil
+		}
+		if ctx.Err() == nil && (errors.Is(fetch.err, context.Canceled) || errors.Is(fetch.err, context.DeadlineExceeded)) {
+			continue
+		}
+		return "", fetch.err
+	}
+}
+
+type apiKeyAuthenticateRequest struct {
+	ApiKey string `json:"apikey"`
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	IamId    string `json:"iamid"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// authServer issues numbered session tokens and only accepts the latest one, expire rotates it
+type authServer struct {
+	mu        sync.Mutex
+	issued    int
+	valid     string
+	requests  int
+	exchanges []map[string]string
+}
+
+func (s *authServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valid = ""
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case apiKeyAuthenticatePath, loginPath:
+		body := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.exchanges = append(s.exchanges, body)
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.issued++
+		s.valid = "session-" + strconv.Itoa(s.issued)
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": s.valid})
+	default:
+		s.requests++
+		if s.valid == "" || r.Header.Get("Authorization") != "Bearer "+s.valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func TestAuth_apiKeyExchangeAndRefresh(t *testing.T) {
+	auth := &authServer{}
+	server := httptest.NewTLSServer(auth)
+	defer server.Close()
+
+	c := newTestClient(server, WithAPIKey("my-key"))
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(auth.exchanges) != 1 || auth.exchanges[0]["apikey"] != "my-key" {
+		t.Fatalf("expected the API key to be exchanged once, got %v", auth.exchanges)
+	}
+
+	auth.expire()
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); err != nil {
+		t.Fatalf("expected the expired token to be refreshed, got %s", err)
+	}
+	if len(auth.exchanges) != 2 {
+		t.Errorf("expected a second exchange after the 401, got %d", len(auth.exchanges))
+	}
+	if auth.requests != 3 {
+		t.Errorf("expected the rejected request to be retried once, got %d requests", auth.requests)
+	}
+}
+
+func TestAuth_passwordLogin(t *testing.T) {
+	auth := &authServer{}
+	server := httptest.NewTLSServer(auth)
+	defer server.Close()
+
+	c := newTestClient(server, WithPasswordLogin("user", "secret", ""))
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{"username": "user", "password": "secret", "iamid": DefaultIamId}
+	for k, v := range expected {
+		if auth.exchanges[0][k] != v {
+			t.Errorf("expected login %s %q, got %q", k, v, auth.exchanges[0][k])
+		}
+	}
+}
+
+func TestAuth_staticTokenIsNotRetried(t *testing.T) {
+	auth := &authServer{}
+	server := httptest.NewTLSServer(auth)
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); !IsUnauthorized(err) {
+		t.Fatalf("expected an unauthorized error, got %v", err)
+	}
+	if auth.requests != 1 {
+		t.Errorf("expected a single request, got %d", auth.requests)
+	}
+}
+
+func TestAuth_refreshOnlyOnce(t *testing.T) {
+	var calls int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var fetched int
+	tokens := newCachingTokenSource(func(_ context.Context) (string, error) {
+		fetched++
+		return "token-" + strconv.Itoa(fetched), nil
+	})
+	c := newTestClient(server, WithTokenSource(tokens))
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); !IsUnauthorized(err) {
+		t.Fatalf("expected an unauthorized error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the request to be retried once, got %d calls", calls)
+	}
+}
+
+func TestAuth_unchangedTokenIsNotRetried(t *testing.T) {
+	var calls int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server, WithTokenSource(CommandToken([]string{"echo", "command-token"})))
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); !IsUnauthorized(err) {
+		t.Fatalf("expected an unauthorized error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the request not to be retried with the same token, got %d calls", calls)
+	}
+}
+
+func TestAuth_concurrentCallersShareAFetch(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var fetched int
+	tokens := newCachingTokenSource(func(_ context.Context) (string, error) {
+		fetched++
+		close(started)
+		<-release
+		return "shared", nil
+	})
+
+	results := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			token, _ := tokens.Token(context.Background())
+			results <- token
+		}()
+	}
+	<-started
+
+	// a caller whose context ends stops waiting without holding up the fetch
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tokens.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		if token := <-results; token != "shared" {
+			t.Errorf("expected the fetched token, got %q", token)
+		}
+	}
+	if fetched != 1 {
+		t.Errorf("expected a single fetch, got %d", fetched)
+	}
+}
+
+func TestAuth_invalidateReportsReplacedToken(t *testing.T) {
+	tokens := newCachingTokenSource(func(_ context.Context) (string, error) {
+		return "current", nil
+	})
+	if _, err := tokens.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, _ := tokens.Invalidate(context.Background(), "stale"); !changed {
+		t.Error("expected a token replaced by another request to count as changed")
+	}
+	if changed, _ := tokens.Invalidate(context.Background(), "current"); changed {
+		t.Error("expected fetching the same token again not to count as changed")
+	}
+}
+
+func TestAuth_fileTokenIsReread(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := FileToken(path)
+
+	token, err := tokens.Token(context.Background())
+	if err != nil || token != "first" {
+		t.Fatalf("expected the token from the file, got %q (%v)", token, err)
+	}
+
+	if err := os.WriteFile(path, []byte("second\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := tokens.Token(context.Background()); token != "first" {
+		t.Errorf("expected the token to be cached until it is rejected, got %q", token)
+	}
+
+	if changed, err := tokens.Invalidate(context.Background(), "first"); !changed || err != nil {
+		t.Fatalf("expected the rejected token to be replaced, got %v (%v)", changed, err)
+	}
+	if token, _ := tokens.Token(context.Background()); token != "second" {
+		t.Errorf("expected the file to be read again, got %q", token)
+	}
+}
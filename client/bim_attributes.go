@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// BimAttributesService manages the attributes (authorizations) of users and groups
+type BimAttributesService interface {
+	// Get returns every attribute of a user or group
+	Get(ctx context.Context, iamId, modelType, modelId string) (*BimAttributeUserResponse, error)
+	Add(ctx context.Context, attribute BimAttribute) error
+	Remove(ctx context.Context, attribute BimAttribute) error
+}
+
+type bimAttributesService struct {
+	client *ImmutaClient
+}
+
+func (s *bimAttributesService) Get(ctx context.Context, iamId, modelType, modelId string) (resp *BimAttributeUserResponse, err error) {
+	err = s.client.GetContext(ctx, fmt.Sprintf("/bim/iam/%s/%s/%s", iamId, modelType, modelId), "", map[string]string{}, &resp)
+	return
+}
+
+func (s *bimAttributesService) Add(ctx context.Context, attribute BimAttribute) error {
+	return s.client.PutContext(ctx, attribute.path(), "", nil, nil)
+}
+
+func (s *bimAttributesService) Remove(ctx context.Context, attribute BimAttribute) error {
+	return s.client.DeleteContext(ctx, attribute.path(), "", nil, nil)
+}
+
+// Domain specific types
+
+// BimAttribute is a single value of an attribute on a user or group
+type BimAttribute struct {
+	IamId     string
+	ModelType string
+	ModelId   string
+	Key       string
+	Value     string
+}
+
+func (a BimAttribute) path() string {
+	return fmt.Sprintf("/bim/iam/%s/%s/%s/authorizations/%s/%s", a.IamId, a.ModelType, a.ModelId, a.Key, a.Value)
+}
+
+type BimAttributeUserResponse struct {
+	BimAuthorizations map[string][]string `json:"bimAuthorizations"`
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BimGroupsService manages groups of the built-in identity manager and their members
+type BimGroupsService interface {
+	Get(ctx context.Context, groupId string) (*BimGroup, error)
+	// Exists reports whether the group exists, a missing group is not an error
+	Exists(ctx context.Context, groupId string) (bool, error)
+	Create(ctx context.Context, group BimGroupInput) (*BimGroup, error)
+	Update(ctx context.Context, groupId string, profile *BimGroupProfile) (*BimGroup, error)
+	Delete(ctx context.Context, groupId string) error
+
+	ListUsers(ctx context.Context, groupId string) (*BimGroupUsers, error)
+	AddUser(ctx context.Context, groupId string, user UserInput) (*GroupUserResponse, error)
+	RemoveUser(ctx context.Context, groupId string, groupUserId string) error
+}
+
+type bimGroupsService struct {
+	client *ImmutaClient
+}
+
+func (s *bimGroupsService) Get(ctx context.Context, groupId string) (bimGroupResponse *BimGroup, err error) {
+	err = s.client.GetContext(ctx, fmt.Sprintf("/bim/group/%s", groupId), "", nil, &bimGroupResponse)
+	return
+}
+
+func (s *bimGroupsService) Exists(ctx context.Context, groupId string) (bool, error) {
+	err := s.client.GetContext(ctx, fmt.Sprintf("/bim/group/%s", groupId), "", nil, nil)
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *bimGroupsService) Create(ctx context.Context, group BimGroupInput) (bimGroupResponse *BimGroup, err error) {
+	err = s.client.PostContext(ctx, "/bim/group", "", group, &bimGroupResponse)
+	return
+}
+
+func (s *bimGroupsService) Update(ctx context.Context, groupId string, profile *BimGroupProfile) (bimGroupResponse *BimGroup, err error) {
+	err = s.client.PutContext(ctx, "/bim/group/"+groupId, "", profile, &bimGroupResponse)
+	return
+}
+
+func (s *bimGroupsService) Delete(ctx context.Context, groupId string) error {
+	return s.client.DeleteContext(ctx, "/bim/group/"+groupId, "", nil, nil)
+}
+
+func (s *bimGroupsService) ListUsers(ctx context.Context, groupId string) (*BimGroupUsers, error) {
+	hits, err := ListAll[BimGroupUser](ctx, s.client, fmt.Sprintf("/bim/group/%s/user", groupId), "", nil, "hits")
+	if err != nil {
+		return nil, err
+	}
+	return &BimGroupUsers{Count: len(hits), Hits: hits}, nil
+}
+
+func (s *bimGroupsService) AddUser(ctx context.Context, groupId string, user UserInput) (groupUserResponse *GroupUserResponse, err error) {
+	err = s.client.PostContext(ctx, fmt.Sprintf("/bim/group/%s/user", groupId), "", user, &groupUserResponse)
+	return
+}
+
+func (s *bimGroupsService) RemoveUser(ctx context.Context, groupId string, groupUserId string) error {
+	return s.client.DeleteContext(ctx, fmt.Sprintf("/bim/group/%s/user/%s", groupId, groupUserId), "", nil, nil)
+}
+
+// Domain specific types
+
+type BimGroupProfile struct {
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type BimGroupInput struct {
+	IamId       string `json:"iamid" tfsdk:"iamid"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type BimGroup struct {
+	BimGroupInput
+	Id             int                    `json:"id"`
+	Authorizations map[string]interface{} `json:"authorizations,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+type BimGroupUserProfile struct {
+	Id              int         `json:"id"`
+	Name            string      `json:"name,omitempty"`
+	Email           string      `json:"email,omitempty"`
+	Phone           string      `json:"phone,omitempty"`
+	About           string      `json:"about,omitempty"`
+	Location        string      `json:"location,omitempty"`
+	Organization    string      `json:"organization,omitempty"`
+	Position        string      `json:"position,omitempty"`
+	Preferences     interface{} `json:"preferences,omitempty"`
+	ExternalUserIds interface{} `json:"externalUserIds,omitempty"`
+	Scim            interface{} `json:"scim,omitempty"`
+	SystemGenerated bool        `json:"systemGenerated"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	UpdatedAt       time.Time   `json:"updatedAt"`
+}
+
+type BimGroupUser struct {
+	Id        int                 `json:"id"`
+	Group     int                 `json:"group"`
+	Profile   BimGroupUserProfile `json:"profile"`
+	UserId    string              `json:"userid"`
+	Uid       int                 `json:"uid"`
+	IamId     string              `json:"iamid"`
+	Disabled  bool                `json:"disabled"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+type BimGroupUsers struct {
+	Count int            `json:"count"`
+	Hits  []BimGroupUser `json:"hits"`
+}
+
+type UserInput struct {
+	UserId string `json:"userid"`
+	IamId  string `json:"iamid"`
+}
+
+type GroupUserResponse struct {
+	Id        int       `json:"id"`
+	Group     int       `json:"group"`
+	Profile   int       `json:"profile"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+)
+
+// BimUsersService manages users of the built-in identity manager
+type BimUsersService interface {
+	List(ctx context.Context) (BimUsers, error)
+	Get(ctx context.Context, userid string) (*BimUser, error)
+	Create(ctx context.Context, user BimUserInput) (*BimUserCreateResponse, error)
+	UpdateProfile(ctx context.Context, userid string, profile *BimUserProfile) (*BimUser, error)
+	Delete(ctx context.Context, userid string) error
+}
+
+type bimUsersService struct {
+	client *ImmutaClient
+}
+
+func (s *bimUsersService) List(ctx context.Context) (bimUsers BimUsers, err error) {
+	bimUsers.Users, err = ListAll[BimUser](ctx, s.client, "/bim/iam/bim/user", "", nil, "users")
+	bimUsers.Count = len(bimUsers.Users)
+	return
+}
+
+func (s *bimUsersService) Get(ctx context.Context, userid string) (bimUserResponse *BimUser, err error) {
+	err = s.client.GetContext(ctx, "/bim/iam/bim/user/"+userid, "", map[string]string{}, &bimUserResponse)
+	return
+}
+
+func (s *bimUsersService) Create(ctx context.Context, user BimUserInput) (bimUserResponse *BimUserCreateResponse, err error) {
+	err = s.client.PostContext(ctx, "/bim/iam/bim/user", "", user, &bimUserResponse)
+	return
+}
+
+func (s *bimUsersService) UpdateProfile(ctx context.Context, userid string, profile *BimUserProfile) (bimUserResponse *BimUser, err error) {
+	err = s.client.PutContext(ctx, "/bim/iam/bim/user/"+userid+"/profile", "", profile, &bimUserResponse)
+	return
+}
+
+func (s *bimUsersService) Delete(ctx context.Context, userid string) error {
+	return s.client.DeleteContext(ctx, "/bim/iam/bim/user/"+userid, "", nil, nil)
+}
+
+// Domain specific types
+
+type BimUserInput struct {
+	Userid      string              `json:"userid"`
+	Password    string              `json:"password"`
+	Profile     BimUserProfileInput `json:"profile"`
+	Permissions []interface{}       `json:"permissions,omitempty"`
+}
+
+type BimUser struct {
+	Userid  string         `json:"userid"`
+	Iamid   string         `json:"iamid"`
+	Profile BimUserProfile `json:"profile"`
+}
+
+type BimUserProfileInput struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type BimUserProfile struct {
+	BimUserProfileInput
+	ExternalUserIds struct {
+		SnowflakeUser string `json:"snowflakeUser,omitempty"`
+	} `json:"externalUserIds,omitempty"`
+}
+
+type BimUserCreateResponse struct {
+	NewUser struct {
+		BimUser
+	} `json:"newUser"`
+}
+
+type BimUsers struct {
+	Users []BimUser `json:"users"`
+	Count int       `json:"count"`
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CassetteMode decides whether a Cassette records live traffic or replays it
+type CassetteMode string
+
+const (
+	// CassetteRecord sends requests to the tenant and keeps every request and response
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay answers requests from a recorded cassette without any network access
+	CassetteReplay CassetteMode = "replay"
+)
+
+// ErrCassetteMissing is returned when replaying a cassette that has not been recorded yet
+var ErrCassetteMissing = errors.New("cassette has not been recorded")
+
+// cassetteScrubbedFields are JSON fields never written to a cassette, matched case-insensitively. They are the
+// fields redacted from logs, so a secret masked in one is never committed with the other.
+var cassetteScrubbedFields = fieldSet(DefaultRedactedFields...)
+
+// cassetteResponseHeaders are the only response headers kept, the rest vary between runs
+var cassetteResponseHeaders = []string{"Content-Type", "Retry-After"}
+
+// Interaction is a single recorded request and its response
+type Interaction struct {
+	Method string `json:"method"`
+	// URI is the path and query of the request, the host is not recorded so a cassette can be replayed against any host
+	URI             string            `json:"uri"`
+	RequestBody     string            `json:"requestBody,omitempty"`
+	StatusCode      int               `json:"statusCode"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	ResponseBody    string            `json:"responseBody,omitempty"`
+
+	replayed bool
+}
+
+// Cassette records the traffic of a client to a fixture file, or replays a fixture file instead of reaching the tenant.
+// Bearer tokens are never recorded and the DefaultRedactedFields of JSON bodies are scrubbed.
+type Cassette struct {
+	Path string
+	Mode CassetteMode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+}
+
+// NewCassette opens a cassette, loading the fixture file when replaying
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	cassette := &Cassette{Path: path, Mode: mode}
+
+	switch mode {
+	case CassetteRecord:
+	case CassetteReplay:
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrCassetteMissing, path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read cassette: %w", err)
+		}
+		if err := json.Unmarshal(content, &cassette.interactions); err != nil {
+			return nil, fmt.Errorf("could not parse cassette %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("cassette mode must be %q or %q, got %q", CassetteRecord, CassetteReplay, mode)
+	}
+
+	return cassette, nil
+}
+
+// WithCassette records or replays every request the client sends
+func WithCassette(cassette *Cassette) Option {
+	return func(c *ImmutaClient) {
+		c.cassette = cassette
+	}
+}
+
+// Save writes the recorded interactions to the fixture file, it does nothing when replaying
+func (c *Cassette) Save() error {
+	if c.Mode != CassetteRecord {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	content, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, append(content, '\n'), 0o644)
+}
+
+// Transport wraps the transport requests are recorded from, it is not used when replaying
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	return &cassetteTransport{cassette: c, next: next}
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cassette.Mode == CassetteReplay {
+		return t.cassette.replay(req)
+	}
+
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	t.cassette.record(req, requestBody, resp, responseBody)
+	return resp, nil
+}
+
+func (c *Cassette) record(req *http.Request, requestBody []byte, resp *http.Response, responseBody []byte) {
+	interaction := &Interaction{
+		Method:          req.Method,
+		URI:             req.URL.RequestURI(),
+		RequestBody:     string(scrubJSON(requestBody, cassetteScrubbedFields)),
+		StatusCode:      resp.StatusCode,
+		ResponseHeaders: map[string]string{},
+		ResponseBody:    string(scrubJSON(responseBody, cassetteScrubbedFields)),
+	}
+	for _, header := range cassetteResponseHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			interaction.ResponseHeaders[header] = value
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+}
+
+// replay answers with the first unused interaction for the same method and URI. Once they have all been used
+// the last one is repeated, so an extra refresh does not fail the replay.
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var match *Interaction
+	for _, interaction := range c.interactions {
+		if interaction.Method != req.Method || interaction.URI != req.URL.RequestURI() {
+			continue
+		}
+		match = interaction
+		if !interaction.replayed {
+			break
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("cassette %s has no recorded response for %s %s", c.Path, req.Method, req.URL.RequestURI())
+	}
+	match.replayed = true
+
+	header := http.Header{}
+	for name, value := range match.ResponseHeaders {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.StatusCode, http.StatusText(match.StatusCode)),
+		StatusCode:    match.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(match.ResponseBody)),
+		ContentLength: int64(len(match.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads the body of a request and puts it back so it can still be sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -1,42 +1,162 @@
 package client
 
 import (
+	"crypto/tls"
 	"fmt"
 	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/hashicorp/go-version"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+const (
+	DefaultMaxIdleConns    = 100
+	DefaultMaxConnsPerHost = 0
+	DefaultIdleConnTimeout = 90 * time.Second
+	DefaultRequestTimeout  = 60 * time.Second
+)
+
 // ImmutaClient is used to make requests to the Immuta API
 type ImmutaClient struct {
 	Host           string
 	DefaultHeaders map[string]string
 	Client         http.Client
-	Timeout        int
+	// Timeout bounds each attempt of a request, including reading the response, 0 means no limit. Registering and
+	// deleting data sources is bounded by the deadline of the context instead when it has one.
+	Timeout   time.Duration
+	Retry     RetryConfig
+	Pool      PoolConfig
+	RateLimit RateLimitConfig
+	// Scheme is https unless the tenant is a plain HTTP stand-in
+	Scheme string
+	// BasePath prefixes every request path, empty when Immuta is served from the root
+	BasePath string
+	// Release of the tenant, nil until DetectRelease succeeds
+	Release *version.Version
+
+	// transport is the pooled transport underneath any wrapping round trippers
+	transport *http.Transport
+	limiter   *limiter
+	tlsConfig *tls.Config
+	proxy     func(*http.Request) (*url.URL, error)
+	tokens    TokenSource
+	cassette  *Cassette
+	// redactedFields are masked in logged bodies on top of DefaultRedactedFields
+	redactedFields []string
+}
+
+// PoolConfig controls how connections to the tenant are kept alive and reused
+type PoolConfig struct {
+	// MaxIdleConns is the number of idle connections kept open, all requests go to the same host
+	MaxIdleConns int
+	// MaxConnsPerHost limits the number of open connections, 0 means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept before being closed
+	IdleConnTimeout time.Duration
+	DisableHTTP2    bool
 }
 
-func NewClient(host, apiToken, userAgent string) *ImmutaClient {
-	httpClient := cleanhttp.DefaultClient()
-	httpClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Immuta", httpClient.Transport)
-	httpClient.Timeout = 60 * time.Second
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxIdleConns:    DefaultMaxIdleConns,
+		MaxConnsPerHost: DefaultMaxConnsPerHost,
+		IdleConnTimeout: DefaultIdleConnTimeout,
+	}
+}
+
+// Option customises an ImmutaClient when it is created
+type Option func(*ImmutaClient)
+
+// WithRetryConfig overrides the default retry behaviour
+func WithRetryConfig(retry RetryConfig) Option {
+	return func(c *ImmutaClient) {
+		c.Retry = retry
+	}
+}
+
+// WithRequestTimeout overrides how long a single attempt of a request may take
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *ImmutaClient) {
+		c.Timeout = timeout
+	}
+}
+
+// WithPoolConfig overrides the default connection pool settings
+func WithPoolConfig(pool PoolConfig) Option {
+	return func(c *ImmutaClient) {
+		c.Pool = pool
+	}
+}
 
+func NewClient(host, apiToken, userAgent string, opts ...Option) *ImmutaClient {
 	client := &ImmutaClient{
 		Host: host,
 		DefaultHeaders: map[string]string{
 			"Content-Type": "application/json",
 			"User-Agent":   userAgent,
 		},
-		Client: *httpClient,
+		Retry:     DefaultRetryConfig(),
+		Pool:      DefaultPoolConfig(),
+		RateLimit: DefaultRateLimitConfig(),
+		Scheme:    SchemeHTTPS,
+		Timeout:   DefaultRequestTimeout,
 	}
 
-	client.DefaultHeaders["Authorization"] = fmt.Sprintf("Bearer %s", apiToken)
+	if apiToken != "" {
+		client.tokens = StaticToken(apiToken)
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	client.limiter = newLimiter(client.RateLimit)
+	client.transport = newPooledTransport(client.Pool)
+	if client.tlsConfig != nil {
+		client.transport.TLSClientConfig = client.tlsConfig
+	}
+	if client.proxy != nil {
+		client.transport.Proxy = client.proxy
+	}
+	var transport http.RoundTripper = client.transport
+	if client.cassette != nil {
+		transport = client.cassette.Transport(transport)
+	}
+	client.Client = http.Client{
+		Transport: newRedactingLoggingTransport(transport, client.redactedFields),
+		Timeout:   client.Timeout,
+	}
 
 	return client
 }
 
+// newPooledTransport builds a transport that keeps connections to the tenant alive between requests
+func newPooledTransport(pool PoolConfig) *http.Transport {
+	transport := cleanhttp.DefaultPooledTransport()
+
+	// every request goes to the same host so the per host limit is the overall limit
+	transport.MaxIdleConns = pool.MaxIdleConns
+	transport.MaxIdleConnsPerHost = pool.MaxIdleConns
+	transport.MaxConnsPerHost = pool.MaxConnsPerHost
+	transport.IdleConnTimeout = pool.IdleConnTimeout
+
+	if pool.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		// a non-nil empty map stops the transport from upgrading to HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return transport
+}
+
+// CloseIdleConnections releases pooled connections, e.g. once the provider is done with the client
+func (c *ImmutaClient) CloseIdleConnections() {
+	c.Client.CloseIdleConnections()
+}
+
 func (c *ImmutaClient) makeUrl(path string) string {
-	return fmt.Sprintf("https://%s%s", c.Host, path)
+	return fmt.Sprintf("%s://%s%s%s", c.Scheme, c.Host, c.BasePath, path)
 }
 
 //func (a *ImmutaClient) Validate(ctx context.Context) error {
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestClient points a client at a TLS test server
+func newTestClient(server *httptest.Server, opts ...Option) *ImmutaClient {
+	c := NewClient(strings.TrimPrefix(server.URL, "https://"), "token", "test", opts...)
+	// trust the test server's certificate while keeping the client's own transport
+	c.transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	return c
+}
+
+// countingServer is a TLS test server that counts the connections opened to it
+func countingServer(connections *int32) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"bimAuthorizations": {}}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(connections, 1)
+		}
+	}
+	server.StartTLS()
+	return server
+}
+
+func TestClient_reusesConnections(t *testing.T) {
+	var connections int32
+	server := countingServer(&connections)
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	for i := 0; i < 10; i++ {
+		if err := c.GetContext(context.Background(), "/bim/iam/bim/user/1", "", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if connections != 1 {
+		t.Errorf("expected a single pooled connection, got %d", connections)
+	}
+}
+
+func TestClient_disableHTTP2(t *testing.T) {
+	pool := DefaultPoolConfig()
+	pool.DisableHTTP2 = true
+
+	c := NewClient("localhost", "token", "test", WithPoolConfig(pool))
+
+	if c.transport.ForceAttemptHTTP2 || c.transport.TLSNextProto == nil {
+		t.Errorf("expected HTTP/2 to be disabled on the transport")
+	}
+}
+
+func benchmarkRequests(b *testing.B, keepAlive bool) {
+	var connections int32
+	server := countingServer(&connections)
+	defer server.Close()
+
+	c := newTestClient(server)
+	// the previous behaviour: a fresh connection and TLS handshake for every request
+	c.transport.DisableKeepAlives = !keepAlive
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		output := map[string]interface{}{}
+		if err := c.GetContext(context.Background(), "/bim/iam/bim/user/1", "", nil, &output); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(connections)/float64(b.N), "conns/op")
+}
+
+func BenchmarkClient_pooledConnections(b *testing.B) {
+	benchmarkRequests(b, true)
+}
+
+func BenchmarkClient_connectionPerRequest(b *testing.B) {
+	benchmarkRequests(b, false)
+}
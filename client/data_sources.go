@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DataSourcesService registers data sources through the V2 API, keyed by their connection key
+type DataSourcesService interface {
+	// Upsert registers the connection's data sources or updates the existing registration
+	Upsert(ctx context.Context, dataSource DataSourceInput) (*DataSourceResponse, error)
+	// Get returns the connection's registered configuration, Immuta leaves out its password and the content of its
+	// user files
+	Get(ctx context.Context, connectionKey string) (*DataSourceInput, error)
+	Delete(ctx context.Context, connectionKey string) error
+	// DryRun reports which data sources an Upsert would create, update or delete without changing anything
+	DryRun(ctx context.Context, dataSource DataSourceInput) (*DataSourceResponse, error)
+	// DryRunDelete reports which data sources a Delete would remove without changing anything
+	DryRunDelete(ctx context.Context, connectionKey string) (*DataSourceResponse, error)
+	// Exists reports whether a connection is registered, a missing connection is not an error
+	Exists(ctx context.Context, connectionKey string) (bool, error)
+}
+
+type dataSourcesService struct {
+	client *ImmutaClient
+}
+
+func (s *dataSourcesService) Upsert(ctx context.Context, dataSource DataSourceInput) (*DataSourceResponse, error) {
+	return s.upsert(ctx, dataSource, false)
+}
+
+func (s *dataSourcesService) DryRun(ctx context.Context, dataSource DataSourceInput) (*DataSourceResponse, error) {
+	return s.upsert(ctx, dataSource, true)
+}
+
+func (s *dataSourcesService) upsert(ctx context.Context, dataSource DataSourceInput, dryRun bool) (dataSourceResponse *DataSourceResponse, err error) {
+	err = s.client.UpsertWithQueryContext(withOperationTimeout(ctx), "/api/v2/data", "", dataSource, map[string]string{"dryRun": strconv.FormatBool(dryRun)}, &dataSourceResponse)
+	return
+}
+
+func (s *dataSourcesService) Get(ctx context.Context, connectionKey string) (dataSource *DataSourceInput, err error) {
+	err = s.client.GetContext(ctx, fmt.Sprintf("/api/v2/data/%s", connectionKey), "", nil, &dataSource)
+	return
+}
+
+func (s *dataSourcesService) Delete(ctx context.Context, connectionKey string) error {
+	return s.client.DeleteContext(withOperationTimeout(ctx), fmt.Sprintf("/api/v2/data/%s", connectionKey), "", nil, nil)
+}
+
+func (s *dataSourcesService) DryRunDelete(ctx context.Context, connectionKey string) (*DataSourceResponse, error) {
+	dataSourceResponse := DataSourceResponse{}
+	err := s.client.DeleteWithQueryContext(
+		ctx,
+		fmt.Sprintf("/api/v2/data/%s", connectionKey),
+		"",
+		nil,
+		map[string]string{"dryRun": "true"},
+		&dataSourceResponse,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &dataSourceResponse, nil
+}
+
+func (s *dataSourcesService) Exists(ctx context.Context, connectionKey string) (bool, error) {
+	_, err := s.DryRunDelete(ctx, connectionKey)
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Domain specific types
+
+type DataSourceNameTemplate struct {
+	DataSourceFormat        string `json:"dataSourceFormat" tfsdk:"data_source_format"`
+	TableFormat             string `json:"tableFormat" tfsdk:"table_format"`
+	SchemaFormat            string `json:"schemaFormat" tfsdk:"schema_format"`
+	SchemaProjectNameFormat string `json:"schemaProjectNameFormat" tfsdk:"schema_project_name_format"`
+}
+
+type DataSourceOptions struct {
+	TableTags                     []string `json:"tableTags,omitempty" tfsdk:"table_tags"`
+	DisableSensitiveDataDiscovery bool     `json:"disableSensitiveDataDiscovery,omitempty" tfsdk:"disable_sensitive_data_discovery"`
+}
+
+type DataSourceOwners struct {
+	Type string `json:"type" tfsdk:"type"`
+	Name string `json:"name" tfsdk:"name"`
+	Iam  string `json:"iam,omitempty" tfsdk:"iam"`
+}
+
+type DataSourceConnection struct {
+	Handler                 string      `json:"handler" tfsdk:"handler"`
+	Hostname                string      `json:"hostname" tfsdk:"hostname"`
+	Port                    int         `json:"port,omitempty" tfsdk:"port"`
+	Database                string      `json:"database" tfsdk:"database"`
+	Schema                  string      `json:"schema,omitempty" tfsdk:"schema"`
+	Username                string      `json:"username" tfsdk:"username"`
+	AuthenticationMethod    string      `json:"authenticationMethod,omitempty" tfsdk:"authentication_method"`
+	Password                string      `json:"password,omitempty" tfsdk:"password"`
+	UserFiles               []UserFiles `json:"userFiles,omitempty" tfsdk:"user_files"`
+	ConnectionStringOptions string      `json:"connectionStringOptions,omitempty" tfsdk:"connection_string_options"`
+	Ssl                     bool        `json:"ssl,omitempty" tfsdk:"ssl"`
+	Warehouse               string      `json:"warehouse,omitempty" tfsdk:"warehouse"`
+	HttpPath                string      `json:"httpPath,omitempty" tfsdk:"http_path"`
+}
+
+type UserFiles struct {
+	Key      string `json:"key" tfsdk:"key"`
+	Content  string `json:"content" tfsdk:"content"`
+	FileName string `json:"fileName" tfsdk:"file_name"`
+}
+
+// DataSourceSource selects one table of the connection. Without sources every table of the connection's schema is
+// registered.
+type DataSourceSource struct {
+	Schema string `json:"schema,omitempty" tfsdk:"schema"`
+	Table  string `json:"table" tfsdk:"table"`
+}
+
+type DataSourceInput struct {
+	ConnectionKey string                 `json:"connectionKey"`
+	NameTemplate  DataSourceNameTemplate `json:"nameTemplate,omitempty"`
+	Options       DataSourceOptions      `json:"options,omitempty"`
+	Owners        []DataSourceOwners     `json:"owners,omitempty"`
+	Connection    DataSourceConnection   `json:"connection"`
+	Sources       []DataSourceSource     `json:"sources,omitempty"`
+}
+
+type DataSourceResponse struct {
+	DryRun   bool     `json:"dryRun"`
+	Creating []string `json:"creating"`
+	Updating []string `json:"updating"`
+	Deleting []string `json:"deleting"`
+	NoChange []string `json:"noChange"`
+	// DetectionRunning reports whether Immuta is still detecting the tables of the connection in the background,
+	// it is also reported by a dry run
+	DetectionRunning bool `json:"detectionRunning"`
+	TagsUpdated      bool `json:"tagsUpdated"`
+}
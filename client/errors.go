@@ -1,35 +1,140 @@
 package client
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
-type RequestError struct {
-	Message    string
+// APIError is returned for any response from the Immuta API with a status code of 400 or above
+type APIError struct {
 	StatusCode int
+	// ErrorCode is the short error name sent by Immuta, e.g. "Bad Request"
+	ErrorCode string
+	Message   string
+	Details   []ErrorDetail
+	// Body is the raw response body, kept for errors that do not use the JSON envelope
+	Body string
 }
 
-type NotFoundError struct {
-	Message    string
-	StatusCode int
+// ErrorDetail describes a problem with a single field of the request
+type ErrorDetail struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// errorEnvelope is the JSON shape of an Immuta error response
+type errorEnvelope struct {
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+	Details    []struct {
+		Field   string   `json:"field"`
+		Path    []string `json:"path"`
+		Message string   `json:"message"`
+	} `json:"details"`
+	Validation struct {
+		Source string   `json:"source"`
+		Keys   []string `json:"keys"`
+	} `json:"validation"`
 }
 
-func NewRequestError(statusCode int, message string) RequestError {
-	return RequestError{
-		Message:    message,
+// NewAPIError builds an APIError from a response, parsing Immuta's JSON error envelope when present
+func NewAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
 		StatusCode: statusCode,
+		ErrorCode:  http.StatusText(statusCode),
+		Body:       string(body),
+	}
+
+	envelope := errorEnvelope{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	if envelope.Error != "" {
+		apiErr.ErrorCode = envelope.Error
 	}
+	apiErr.Message = envelope.Message
+
+	for _, detail := range envelope.Details {
+		field := detail.Field
+		if field == "" {
+			field = strings.Join(detail.Path, ".")
+		}
+		apiErr.Details = append(apiErr.Details, ErrorDetail{Field: field, Message: detail.Message})
+	}
+	for _, key := range envelope.Validation.Keys {
+		apiErr.Details = append(apiErr.Details, ErrorDetail{Field: key, Message: fmt.Sprintf("invalid %s value", envelope.Validation.Source)})
+	}
+
+	return apiErr
 }
 
-func (err RequestError) Error() string {
-	return fmt.Sprintf("(%d) %s", err.StatusCode, err.Message)
+func (err *APIError) Error() string {
+	message := err.Message
+	if message == "" {
+		message = err.Body
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("(%d) %s", err.StatusCode, err.ErrorCode))
+	if message != "" {
+		sb.WriteString(": ")
+		sb.WriteString(message)
+	}
+	for _, detail := range err.Details {
+		sb.WriteString(fmt.Sprintf("\n  %s: %s", detail.Field, detail.Message))
+	}
+	return sb.String()
+}
+
+// AsAPIError returns the APIError in err's chain, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
 }
 
-func NewNotFoundError(message string) NotFoundError {
-	return NotFoundError{
-		Message:    message,
-		StatusCode: 404,
+func hasStatus(err error, statusCodes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
 	}
+	for _, statusCode := range statusCodes {
+		if apiErr.StatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an Immuta API 404
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsMethodNotAllowed reports whether err is an Immuta API 405, e.g. for an endpoint the tenant does not serve
+func IsMethodNotAllowed(err error) bool {
+	return hasStatus(err, http.StatusMethodNotAllowed)
+}
+
+// IsConflict reports whether err is an Immuta API 409
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsValidation reports whether Immuta rejected the request payload
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
 }
 
-func (err NotFoundError) Error() string {
-	return fmt.Sprintf("NotFoundError (%d) %s", err.StatusCode, err.Message)
+// IsUnauthorized reports whether Immuta rejected the credentials used for the request
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
 }
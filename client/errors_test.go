@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError_parsesEnvelope(t *testing.T) {
+	body := []byte(`{
+		"statusCode": 400,
+		"error": "Bad Request",
+		"message": "child \"name\" fails",
+		"validation": {"source": "payload", "keys": ["name"]}
+	}`)
+
+	apiErr := NewAPIError(http.StatusBadRequest, body)
+
+	if apiErr.ErrorCode != "Bad Request" {
+		t.Errorf("unexpected error code %q", apiErr.ErrorCode)
+	}
+	if apiErr.Message != `child "name" fails` {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "name" {
+		t.Errorf("expected a detail for the name field, got %+v", apiErr.Details)
+	}
+}
+
+func TestAPIError_fieldDetails(t *testing.T) {
+	body := []byte(`{"statusCode": 422, "error": "Unprocessable Entity", "message": "invalid", "details": [{"path": ["connection", "hostname"], "message": "is required"}]}`)
+
+	apiErr := NewAPIError(http.StatusUnprocessableEntity, body)
+
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "connection.hostname" || apiErr.Details[0].Message != "is required" {
+		t.Errorf("unexpected details %+v", apiErr.Details)
+	}
+}
+
+func TestAPIError_plainBody(t *testing.T) {
+	apiErr := NewAPIError(http.StatusBadGateway, []byte("upstream unavailable\n"))
+
+	if apiErr.Message != "upstream unavailable" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+	if apiErr.ErrorCode != "Bad Gateway" {
+		t.Errorf("unexpected error code %q", apiErr.ErrorCode)
+	}
+}
+
+func TestAPIError_helpers(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		check      func(error) bool
+	}{
+		{http.StatusNotFound, IsNotFound},
+		{http.StatusConflict, IsConflict},
+		{http.StatusBadRequest, IsValidation},
+		{http.StatusUnprocessableEntity, IsValidation},
+		{http.StatusUnauthorized, IsUnauthorized},
+	}
+
+	for _, tc := range cases {
+		// wrap the error to make sure the helpers walk the chain
+		err := fmt.Errorf("wrapped: %w", NewAPIError(tc.statusCode, nil))
+		if !tc.check(err) {
+			t.Errorf("expected helper to match status %d", tc.statusCode)
+		}
+		if tc.check(fmt.Errorf("(%d) not an api error", tc.statusCode)) {
+			t.Errorf("expected helper not to match a plain error for status %d", tc.statusCode)
+		}
+	}
+}
+
+func TestAPIError_returnedByClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"statusCode": 404, "error": "Not Found", "message": "Group not found"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	err := c.Get("/bim/group/1", "", nil, nil)
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	apiErr, _ := AsAPIError(err)
+	if apiErr.Message != "Group not found" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+const (
+	// loggingSubsystem is the tflog subsystem requests and responses are logged under
+	loggingSubsystem = "immuta"
+	// requestIdHeader identifies a request in Immuta's own logs, when the tenant sends it
+	requestIdHeader = "X-Request-Id"
+)
+
+// DefaultRedactedFields are JSON fields masked in logged request and response bodies, wherever they appear. They
+// cover every secret of DataSourceConnection: content holds the keys uploaded as user files, clientSecret the OAuth
+// client secret and privateKeyPassword the passphrase of an encrypted private key.
+var DefaultRedactedFields = []string{"password", "content", "privateKey", "privateKeyPassword", "clientSecret", "token", "apikey"}
+
+// redactedHeaders carry credentials and are masked in logs
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// WithRedactedFields masks more JSON fields in logged bodies, on top of DefaultRedactedFields
+func WithRedactedFields(fields ...string) Option {
+	return func(c *ImmutaClient) {
+		c.redactedFields = append(c.redactedFields, fields...)
+	}
+}
+
+// redactingLoggingTransport logs every request and response at debug level, like the SDK's logging transport,
+// with credentials masked in headers and bodies
+type redactingLoggingTransport struct {
+	fields map[string]bool
+	next   http.RoundTripper
+}
+
+func newRedactingLoggingTransport(next http.RoundTripper, extraFields []string) *redactingLoggingTransport {
+	return &redactingLoggingTransport{
+		fields: fieldSet(append(append([]string{}, DefaultRedactedFields...), extraFields...)...),
+		next:   next,
+	}
+}
+
+func (t *redactingLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transactionId, err := uuid.GenerateUUID()
+	if err != nil {
+		transactionId = "Unable to assign Transaction ID: " + err.Error()
+	}
+	ctx := tflog.SubsystemSetField(req.Context(), loggingSubsystem, logging.FieldHttpTransactionId, transactionId)
+
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := t.headerFields(req.Header)
+	fields[logging.FieldHttpOperationType] = logging.OperationHttpRequest
+	fields[logging.FieldHttpRequestMethod] = req.Method
+	fields[logging.FieldHttpRequestUri] = req.URL.RequestURI()
+	fields[logging.FieldHttpRequestProtoVersion] = req.Proto
+	fields[logging.FieldHttpRequestBody] = string(scrubJSON(requestBody, t.fields))
+	tflog.SubsystemDebug(ctx, loggingSubsystem, "Sending HTTP Request", fields)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	fields = t.headerFields(resp.Header)
+	fields[logging.FieldHttpOperationType] = logging.OperationHttpResponse
+	fields[logging.FieldHttpResponseProtoVersion] = resp.Proto
+	fields[logging.FieldHttpResponseStatusCode] = resp.StatusCode
+	fields[logging.FieldHttpResponseStatusReason] = strings.TrimSpace(strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)))
+	fields[logging.FieldHttpResponseBody] = string(scrubJSON(responseBody, t.fields))
+	tflog.SubsystemDebug(ctx, loggingSubsystem, "Received HTTP Response", fields)
+
+	return resp, nil
+}
+
+func (t *redactingLoggingTransport) headerFields(header http.Header) map[string]interface{} {
+	fields := make(map[string]interface{}, len(header)+6)
+	for name, values := range header {
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			fields[name] = redactedValue
+			continue
+		}
+		fields[name] = strings.Join(values, ", ")
+	}
+	return fields
+}
+
+// requestLog collects what is logged about a client call, across its retries, once it is done
+type requestLog struct {
+	method    string
+	path      string
+	start     time.Time
+	retries   int
+	status    int
+	requestId string
+}
+
+func newRequestLog(method, path string) *requestLog {
+	return &requestLog{method: method, path: path, start: time.Now()}
+}
+
+// observe records the status and request ID of the latest attempt
+func (l *requestLog) observe(response *http.Response) {
+	if response == nil {
+		return
+	}
+	l.status = response.StatusCode
+	l.requestId = response.Header.Get(requestIdHeader)
+}
+
+// retry records another attempt of the call, err is the transport error of the failed attempt if any
+func (l *requestLog) retry(ctx context.Context, delay time.Duration, err error) {
+	l.retries++
+
+	fields := l.fields()
+	fields["delay_ms"] = delay.Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	tflog.SubsystemDebug(ctx, loggingSubsystem, "Retrying Immuta request", fields)
+}
+
+func (l *requestLog) write(ctx context.Context, err error) {
+	fields := l.fields()
+	fields["latency_ms"] = time.Since(l.start).Milliseconds()
+	if err == nil {
+		tflog.SubsystemDebug(ctx, loggingSubsystem, "Immuta request succeeded", fields)
+		return
+	}
+
+	fields["error"] = err.Error()
+	// client errors such as a 404 are often expected, e.g. when checking whether something still exists
+	if l.status >= 400 && l.status < 500 {
+		tflog.SubsystemDebug(ctx, loggingSubsystem, "Immuta request failed", fields)
+		return
+	}
+	tflog.SubsystemWarn(ctx, loggingSubsystem, "Immuta request failed", fields)
+}
+
+func (l *requestLog) fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"method":  l.method,
+		"path":    l.path,
+		"retries": l.retries,
+	}
+	if l.status != 0 {
+		fields["status"] = l.status
+	}
+	if l.requestId != "" {
+		fields["request_id"] = l.requestId
+	}
+	return fields
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLogging_redactsSecrets(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=cookie-secret")
+		_, _ = w.Write([]byte(`{"token": "response-token", "connectionKey": "snowflake"}`))
+	}))
+	defer server.Close()
+
+	output := bytes.Buffer{}
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c := newTestClient(server, WithTokenSource(StaticToken("bearer-secret")), WithRedactedFields("warehouse"))
+	input := DataSourceInput{
+		ConnectionKey: "snowflake",
+		Connection: DataSourceConnection{
+			Handler:   "Snowflake",
+			Username:  "svc",
+			Password:  "password-secret",
+			Warehouse: "warehouse-secret",
+			UserFiles: []UserFiles{{Key: "privateKey", Content: "content-secret", FileName: "key.p8"}},
+		},
+	}
+	if err := c.PostContext(ctx, "/api/v2/data", "", input, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := output.String()
+	for _, secret := range []string{"bearer-secret", "password-secret", "content-secret", "warehouse-secret", "response-token", "cookie-secret"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %q to be redacted from the logs:\n%s", secret, logs)
+		}
+	}
+	for _, kept := range []string{"Sending HTTP Request", "Received HTTP Response", "/api/v2/data", `\"username\":\"svc\"`} {
+		if !strings.Contains(logs, kept) {
+			t.Errorf("expected %q in the logs:\n%s", kept, logs)
+		}
+	}
+}
+
+func TestLogging_requestFields(t *testing.T) {
+	var calls int32
+	failing := failingHandler(1, http.StatusServiceUnavailable, &calls)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIdHeader, "request-42")
+		failing(w, r)
+	}))
+	defer server.Close()
+
+	output := bytes.Buffer{}
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = tflog.SetField(ctx, "resource", "immuta_purpose")
+
+	c := newTestClient(server, fastRetries(3))
+	if err := c.GetContext(ctx, "/governance/purpose", "", map[string]string{"size": "10"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var retried, succeeded map[string]interface{}
+	for _, entry := range entries {
+		switch entry["@message"] {
+		case "Retrying Immuta request":
+			retried = entry
+		case "Immuta request succeeded":
+			succeeded = entry
+		}
+	}
+	if retried == nil || retried["status"] != float64(http.StatusServiceUnavailable) {
+		t.Errorf("expected the retry to be logged with the failed status, got %v", retried)
+	}
+	if succeeded == nil {
+		t.Fatalf("expected the call to be logged:\n%v", entries)
+	}
+
+	expected := map[string]interface{}{
+		"@module":    "provider.immuta",
+		"method":     http.MethodGet,
+		"path":       "/governance/purpose",
+		"status":     float64(http.StatusOK),
+		"retries":    float64(1),
+		"request_id": "request-42",
+		"resource":   "immuta_purpose",
+	}
+	for key, value := range expected {
+		if succeeded[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, succeeded[key])
+		}
+	}
+	if _, ok := succeeded["latency_ms"]; !ok {
+		t.Error("expected the latency to be logged")
+	}
+}
+
+func TestDefaultRedactedFields_coverDataSourceSecrets(t *testing.T) {
+	secrets := []string{"password-secret", "key-content-secret", "credentials-secret"}
+	input := DataSourceInput{
+		ConnectionKey: "snowflake",
+		Connection: DataSourceConnection{
+			Handler:                 "Snowflake",
+			Hostname:                "example.snowflakecomputing.com",
+			Port:                    443,
+			Database:                "ANALYTICS",
+			Schema:                  "PUBLIC",
+			Username:                "svc",
+			Password:                "password-secret",
+			ConnectionStringOptions: "role=ANALYST",
+			Ssl:                     true,
+			Warehouse:               "COMPUTE_WH",
+			HttpPath:                "/sql/1.0/warehouses/abc",
+			UserFiles: []UserFiles{
+				{Key: "privateKey", Content: "key-content-secret", FileName: "private_key.p8"},
+				{Key: "keyFile", Content: "credentials-secret", FileName: "credentials.json"},
+			},
+		},
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := string(scrubJSON(body, fieldSet(DefaultRedactedFields...)))
+	for _, secret := range secrets {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("expected %q to be redacted:\n%s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{`"username":"svc"`, `"fileName":"private_key.p8"`} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("expected %s to be kept:\n%s", kept, redacted)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const DefaultPageSize = 100
+
+// Paginator streams every page of an Immuta list endpoint using the offset/size query parameters.
+// Responses are either a bare JSON array or an object holding the items under ItemsKey (usually "hits")
+// alongside the total "count".
+type Paginator[T any] struct {
+	client   *ImmutaClient
+	path     string
+	version  string
+	query    map[string]string
+	itemsKey string
+	pageSize int
+
+	offset int
+	// count is the total reported by the API, -1 when the endpoint does not report one
+	count int
+	done  bool
+	page  []T
+	err   error
+}
+
+// NewPaginator creates a Paginator, pass an empty itemsKey for endpoints returning a bare array
+func NewPaginator[T any](c *ImmutaClient, path, version string, query map[string]string, itemsKey string) *Paginator[T] {
+	return &Paginator[T]{
+		client:   c,
+		path:     path,
+		version:  version,
+		query:    query,
+		itemsKey: itemsKey,
+		pageSize: DefaultPageSize,
+		count:    -1,
+	}
+}
+
+// WithPageSize changes the number of items requested per page
+func (p *Paginator[T]) WithPageSize(size int) *Paginator[T] {
+	if size > 0 {
+		p.pageSize = size
+	}
+	return p
+}
+
+// Next fetches the next page, returning false once every page has been read or an error occurred
+func (p *Paginator[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	query := map[string]string{}
+	for k, v := range p.query {
+		query[k] = v
+	}
+	query["offset"] = strconv.Itoa(p.offset)
+	query["size"] = strconv.Itoa(p.pageSize)
+
+	var raw json.RawMessage
+	if err := p.client.GetContext(ctx, p.path, p.version, query, &raw); err != nil {
+		p.err = err
+		return false
+	}
+
+	page, count, err := p.decode(raw)
+	if err != nil {
+		p.err = fmt.Errorf("could not decode page of %s: %w", p.path, err)
+		return false
+	}
+
+	// an endpoint ignoring offset serves the first page again, which would otherwise be read forever
+	if p.offset > 0 && len(page) > 0 && reflect.DeepEqual(page, p.page) {
+		p.err = fmt.Errorf("%s returned the same page for offsets %d and %d, it does not seem to honor offset", p.path, p.offset-len(page), p.offset)
+		return false
+	}
+
+	p.page = page
+	p.count = count
+	p.offset += len(page)
+
+	switch {
+	case len(page) == 0:
+		p.done = true
+	case p.count >= 0:
+		// servers may cap the page size below the one requested, only the reported total ends the iteration
+		p.done = p.offset >= p.count
+	default:
+		// a short page is the last one, a longer one means the endpoint ignored size and returned everything
+		p.done = len(page) != p.pageSize
+	}
+
+	return len(page) > 0
+}
+
+func (p *Paginator[T]) decode(raw json.RawMessage) ([]T, int, error) {
+	page := make([]T, 0)
+	if len(raw) == 0 {
+		return page, -1, nil
+	}
+
+	if p.itemsKey == "" {
+		err := json.Unmarshal(raw, &page)
+		return page, -1, err
+	}
+
+	envelope := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, -1, err
+	}
+
+	if items, ok := envelope[p.itemsKey]; ok && string(items) != "null" {
+		if err := json.Unmarshal(items, &page); err != nil {
+			return nil, -1, err
+		}
+	}
+
+	count := -1
+	if rawCount, ok := envelope["count"]; ok {
+		if err := json.Unmarshal(rawCount, &count); err != nil {
+			return nil, -1, err
+		}
+	}
+
+	return page, count, nil
+}
+
+// Page returns the items of the page fetched by the last call to Next
+func (p *Paginator[T]) Page() []T {
+	return p.page
+}
+
+// Err returns the error that stopped the iteration, if any
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// ListAll reads every page of a list endpoint
+func ListAll[T any](ctx context.Context, c *ImmutaClient, path, version string, query map[string]string, itemsKey string) ([]T, error) {
+	items := make([]T, 0)
+	paginator := NewPaginator[T](c, path, version, query, itemsKey)
+	for paginator.Next(ctx) {
+		items = append(items, paginator.Page()...)
+	}
+	return items, paginator.Err()
+}
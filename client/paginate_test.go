@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type testItem struct {
+	Id int `json:"id"`
+}
+
+// pagedHandler serves `total` items using the offset/size conventions, wrapped in itemsKey unless it is empty
+func pagedHandler(t *testing.T, total int, itemsKey string, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		size, err := strconv.Atoi(r.URL.Query().Get("size"))
+		if err != nil {
+			t.Errorf("expected a size parameter, got %q", r.URL.Query().Get("size"))
+		}
+
+		page := make([]testItem, 0)
+		for i := offset; i < offset+size && i < total; i++ {
+			page = append(page, testItem{Id: i})
+		}
+
+		var body interface{} = page
+		if itemsKey != "" {
+			body = map[string]interface{}{itemsKey: page, "count": total}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func TestPaginator_hitsAndCount(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(pagedHandler(t, 25, "hits", &requests))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	paginator := NewPaginator[testItem](c, "/bim/group/1/user", "", nil, "hits").WithPageSize(10)
+	items := make([]testItem, 0)
+	for paginator.Next(context.Background()) {
+		items = append(items, paginator.Page()...)
+	}
+
+	if err := paginator.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(items) != 25 {
+		t.Fatalf("expected 25 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.Id != i {
+			t.Errorf("expected item %d, got %d", i, item.Id)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestPaginator_stopsAtReportedCount(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(pagedHandler(t, 20, "hits", &requests))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	items, err := ListAll[testItem](context.Background(), c, "/project", "", nil, "hits")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(items) != 20 {
+		t.Errorf("expected 20 items, got %d", len(items))
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request when the first page holds every item, got %d", requests)
+	}
+}
+
+func TestPaginator_bareArray(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(pagedHandler(t, 7, "", &requests))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	paginator := NewPaginator[testItem](c, "/tag", "", map[string]string{"searchText": "x"}, "").WithPageSize(5)
+	items := make([]testItem, 0)
+	for paginator.Next(context.Background()) {
+		items = append(items, paginator.Page()...)
+	}
+
+	if err := paginator.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(items) != 7 {
+		t.Errorf("expected 7 items, got %d", len(items))
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestPaginator_countOverShortPages(t *testing.T) {
+	requests := 0
+	paged := pagedHandler(t, 10, "hits", &requests)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server caps pages at 4 items whatever size was requested
+		query := r.URL.Query()
+		query.Set("size", "4")
+		r.URL.RawQuery = query.Encode()
+		paged(w, r)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	paginator := NewPaginator[testItem](c, "/project", "", nil, "hits").WithPageSize(10)
+	items := make([]testItem, 0)
+	for paginator.Next(context.Background()) {
+		items = append(items, paginator.Page()...)
+	}
+
+	if err := paginator.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(items) != 10 {
+		t.Errorf("expected the 10 reported items, got %d", len(items))
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestPaginator_bareArrayIgnoringOffset(t *testing.T) {
+	requests := 0
+	paged := pagedHandler(t, 12, "", &requests)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Del("offset")
+		r.URL.RawQuery = query.Encode()
+		paged(w, r)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	paginator := NewPaginator[testItem](c, "/tag", "", nil, "").WithPageSize(5)
+	for paginator.Next(context.Background()) {
+	}
+	if paginator.Err() == nil {
+		t.Fatal("expected an error for an endpoint serving the same page again")
+	}
+	if requests != 2 {
+		t.Errorf("expected to stop at the repeated page, got %d requests", requests)
+	}
+}
+
+func TestPaginator_bareArrayIgnoringSize(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := make([]testItem, 0)
+		for i := 0; i < 7; i++ {
+			page = append(page, testItem{Id: i})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	items, err := ListAll[testItem](context.Background(), c, "/tag", "", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(items) != 7 {
+		t.Errorf("expected 7 items, got %d", len(items))
+	}
+	if requests != 1 {
+		t.Errorf("expected a page longer than requested to be the whole list, got %d requests", requests)
+	}
+}
+
+func TestPaginator_error(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	_, err := ListAll[testItem](context.Background(), c, "/bim/group/1/user", "", nil, "hits")
+	if !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProjectsService manages projects and the acknowledgement of their purposes
+type ProjectsService interface {
+	List(ctx context.Context) (Projects, error)
+	// Find searches for a project by name, preferring an exact match over the first search hit
+	Find(ctx context.Context, name string) (*Project, error)
+	Get(ctx context.Context, id string) (*Project, error)
+	// Upsert creates the project or updates the one with the same project key
+	Upsert(ctx context.Context, project ProjectInput) (*ProjectResourceResponseV2, error)
+	Delete(ctx context.Context, projectKey string) error
+	// Acknowledge accepts the acknowledgements of the project's purposes on behalf of a member
+	Acknowledge(ctx context.Context, projectId int, memberId int) error
+}
+
+type projectsService struct {
+	client *ImmutaClient
+}
+
+func (s *projectsService) List(ctx context.Context) (projects Projects, err error) {
+	projects.Projects, err = ListAll[Project](ctx, s.client, "/project", "", nil, "hits")
+	projects.Count = len(projects.Projects)
+	return
+}
+
+func (s *projectsService) Find(ctx context.Context, name string) (*Project, error) {
+	var project *Project
+	paginator := NewPaginator[Project](s.client, "/project", "", map[string]string{"searchText": name, "nameOnly": "true"}, "hits")
+	for paginator.Next(ctx) {
+		for _, hit := range paginator.Page() {
+			hit := hit
+			if hit.Name == name {
+				return &hit, nil
+			}
+			if project == nil {
+				project = &hit
+			}
+		}
+	}
+	if err := paginator.Err(); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("no project found with name %q", name)
+	}
+	return project, nil
+}
+
+func (s *projectsService) Get(ctx context.Context, id string) (project *Project, err error) {
+	err = s.client.GetContext(ctx, fmt.Sprintf("/project/%s", id), "", nil, &project)
+	return
+}
+
+func (s *projectsService) Upsert(ctx context.Context, project ProjectInput) (projectResponse *ProjectResourceResponseV2, err error) {
+	err = s.client.UpsertContext(ctx, "/api/v2/project", "", project, &projectResponse)
+	return
+}
+
+func (s *projectsService) Delete(ctx context.Context, projectKey string) error {
+	return s.client.DeleteContext(ctx, fmt.Sprintf("/api/v2/project/%s", projectKey), "", nil, nil)
+}
+
+func (s *projectsService) Acknowledge(ctx context.Context, projectId int, memberId int) error {
+	return s.client.PostContext(ctx, fmt.Sprintf("/project/%d/members/%d/acknowledge", projectId, memberId), "", AcknowledgePayload{}, nil)
+}
+
+// IsAcknowledgementRequired detects the error returned when the project's purposes have not been acknowledged yet
+func IsAcknowledgementRequired(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && strings.Contains(apiErr.Message, "You must first acknowledge")
+}
+
+// Domain specific types
+
+type AcknowledgePayload struct{}
+
+type ProjectInput struct {
+	Name               string                 `json:"name"`
+	ProjectKey         string                 `json:"projectKey"`
+	Description        string                 `json:"description,omitempty"`
+	Documentation      string                 `json:"documentation,omitempty"`
+	AllowMaskedJoins   bool                   `json:"allowMaskedJoins,omitempty"`
+	SubscriptionPolicy map[string]interface{} `json:"subscriptionPolicy,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+	Purposes           []string               `json:"purposes,omitempty"`
+}
+
+type Project struct {
+	ProjectInput
+	Tags           []Tag     `json:"tags"`
+	Purposes       []Purpose `json:"purposes"`
+	Id             int       `json:"id"`
+	Status         string    `json:"status"`
+	Deleted        bool      `json:"deleted"`
+	SubscriptionId int       `json:"subscriptionId"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdateAt       time.Time `json:"updatedAt"`
+}
+
+type ProjectResourceResponseV2 struct {
+	DryRun                     bool `json:"dryRun"`
+	Creating                   bool `json:"creating"`
+	Updating                   bool `json:"updating"`
+	NumberOfDataSourcesAdded   int  `json:"numberOfDataSourcesAdded"`
+	NumberOfDataSourcesRemoved int  `json:"numberOfDataSourcesRemoved"`
+	CreatingWorkspace          bool `json:"creatingWorkspace"`
+	DeleteWorkspace            bool `json:"deletingWorkspace"`
+	ProjectId                  int  `json:"projectId"`
+}
+
+type Projects struct {
+	Projects []Project `json:"projects"`
+	Count    int       `json:"count"`
+}
+
+type FindProjectsResponse struct {
+	Hits   []Project `json:"hits"`
+	Facets struct{}  `json:"facets"`
+	Count  int       `json:"count"`
+}
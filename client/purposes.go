@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurposesService manages purposes and their subpurposes
+type PurposesService interface {
+	List(ctx context.Context) (Purposes, error)
+	Get(ctx context.Context, id string) (*PurposeResponse, error)
+	// Upsert creates the purpose or updates the one with the same name
+	Upsert(ctx context.Context, purpose PurposeInput) (*PurposeResourceResponseV2, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type purposesService struct {
+	client *ImmutaClient
+}
+
+func (s *purposesService) List(ctx context.Context) (purposes Purposes, err error) {
+	purposes.Purposes, err = ListAll[PurposeResponse](ctx, s.client, "/governance/purpose", "", nil, "purposes")
+	purposes.Count = len(purposes.Purposes)
+	return
+}
+
+func (s *purposesService) Get(ctx context.Context, id string) (purpose *PurposeResponse, err error) {
+	err = s.client.GetContext(ctx, fmt.Sprintf("/governance/purpose/%s", id), "", map[string]string{"includeSubpurposes": "true"}, &purpose)
+	return
+}
+
+func (s *purposesService) Upsert(ctx context.Context, purpose PurposeInput) (purposeResponse *PurposeResourceResponseV2, err error) {
+	err = s.client.UpsertContext(ctx, "/api/v2/purpose", "", purpose, &purposeResponse)
+	return
+}
+
+func (s *purposesService) Delete(ctx context.Context, id string) error {
+	return s.client.DeleteContext(ctx, fmt.Sprintf("/governance/purpose/%s", id), "", nil, nil)
+}
+
+// Domain specific types
+
+type Purpose struct {
+	Name            string `json:"name" tfsdk:"name"`
+	Description     string `json:"description" tfsdk:"description"`
+	Acknowledgement string `json:"acknowledgement" tfsdk:"acknowledgement"`
+}
+
+type PurposeInput struct {
+	Purpose
+	Subpurposes []Purpose `json:"subpurposes,omitempty"`
+}
+
+type PurposeResponse struct {
+	PurposeInput
+	Id                     int         `json:"id"`
+	AddedByProfile         int         `json:"addedByProfile"`
+	DisplayAcknowledgement bool        `json:"displayAcknowledgement"`
+	Deleted                bool        `json:"deleted"`
+	SystemGenerated        bool        `json:"systemGenerated"`
+	PolicyMetadata         interface{} `json:"policyMetadata"`
+	CreatedAt              time.Time   `json:"createdAt"`
+	UpdatedAt              time.Time   `json:"updatedAt"`
+}
+
+type PurposeResourceResponseV2 struct {
+	DryRun    bool `json:"dryRun"`
+	Creating  bool `json:"creating"`
+	Updating  bool `json:"updating"`
+	PurposeId int  `json:"purposeId"`
+}
+
+type Purposes struct {
+	Purposes []PurposeResponse `json:"purposes"`
+	Count    int               `json:"count"`
+}
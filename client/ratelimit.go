@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRequestsPerSecond of 0 means requests are not rate limited
+	DefaultRequestsPerSecond = 0
+	// DefaultMaxConcurrentRequests of 0 means the number of in-flight requests is not limited
+	DefaultMaxConcurrentRequests = 0
+)
+
+// RateLimitConfig controls how quickly requests are sent to the tenant. Every resource of a provider
+// shares the same client, so the limits apply to the provider as a whole.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the steady rate requests are sent at, 0 disables rate limiting
+	RequestsPerSecond float64
+	// Burst is the number of requests that can be sent at once before the rate applies,
+	// defaults to RequestsPerSecond rounded up
+	Burst int
+	// MaxConcurrent limits the number of requests in flight, 0 means no limit
+	MaxConcurrent int
+}
+
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerSecond: DefaultRequestsPerSecond,
+		MaxConcurrent:     DefaultMaxConcurrentRequests,
+	}
+}
+
+// WithRateLimitConfig overrides the default rate and concurrency limits
+func WithRateLimitConfig(rateLimit RateLimitConfig) Option {
+	return func(c *ImmutaClient) {
+		c.RateLimit = rateLimit
+	}
+}
+
+// limiter combines a token bucket with a semaphore bounding the requests in flight
+type limiter struct {
+	bucket *tokenBucket
+	slots  chan struct{}
+}
+
+func newLimiter(config RateLimitConfig) *limiter {
+	l := &limiter{}
+
+	if config.RequestsPerSecond > 0 {
+		burst := config.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(config.RequestsPerSecond))
+		}
+		l.bucket = newTokenBucket(config.RequestsPerSecond, burst, time.Now)
+	}
+
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+
+	return l
+}
+
+// acquire blocks until a request may be sent, the returned func must be called once it has completed
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l == nil {
+		return release, nil
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-l.slots })
+		}
+	}
+
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// releasingBody frees the limiter slot once the response body is closed, so a request is in flight
+// until its response has been read
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// tokenBucket hands out tokens at a steady rate, allowing bursts up to its capacity
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:     rate,
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		last:     now(),
+		now:      now,
+	}
+}
+
+// reserve takes a token, going into debt if none are left, and returns how long to wait before using it
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel hands back a reserved token that was not used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.capacity, b.tokens+1)
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_tokenBucket(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(2, 2, func() time.Time { return now })
+
+	// the burst is available straight away
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Fatalf("request %d: expected no delay, got %s", i, delay)
+		}
+	}
+
+	if delay := bucket.reserve(); delay != 500*time.Millisecond {
+		t.Errorf("expected to wait for the next token, got %s", delay)
+	}
+	if delay := bucket.reserve(); delay != time.Second {
+		t.Errorf("expected to queue behind the previous request, got %s", delay)
+	}
+
+	// the bucket refills, but never beyond its capacity
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Fatalf("request %d after refill: expected no delay, got %s", i, delay)
+		}
+	}
+	if delay := bucket.reserve(); delay == 0 {
+		t.Errorf("expected the refilled bucket to be capped at its burst")
+	}
+}
+
+func TestRateLimit_cancelledWait(t *testing.T) {
+	c := NewClient("localhost", "token", "test", WithRateLimitConfig(RateLimitConfig{RequestsPerSecond: 0.1, Burst: 1}))
+
+	if _, err := c.limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.limiter.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to be cut short by the context, got %v", err)
+	}
+}
+
+func TestRateLimit_maxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server, WithRateLimitConfig(RateLimitConfig{MaxConcurrent: 2}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.GetContext(context.Background(), "/test", "", nil, nil); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+	if len(c.limiter.slots) != 0 {
+		t.Errorf("expected every slot to be released, %d still held", len(c.limiter.slots))
+	}
+}
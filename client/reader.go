@@ -11,12 +11,13 @@ func (c *ImmutaClient) unmarshall(reader io.ReadCloser, output interface{}) erro
 		_ = reader.Close()
 	}()
 
+	content, _ := ioutil.ReadAll(reader)
+
+	// the body is always read to the end so the connection can be reused
 	if output == nil {
 		return nil
 	}
 
-	content, _ := ioutil.ReadAll(reader)
-
 	if len(content) > 0 {
 		err := json.Unmarshal(content, output)
 		if err != nil {
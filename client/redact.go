@@ -0,0 +1,54 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redactedValue replaces secrets in logs and cassettes
+const redactedValue = "REDACTED"
+
+// fieldSet builds a case-insensitive set of JSON field names
+func fieldSet(fields ...string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(field)] = true
+	}
+	return set
+}
+
+// scrubJSON replaces the values of the given fields, at any depth, bodies that are not JSON are returned unchanged
+func scrubJSON(body []byte, fields map[string]bool) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+
+	scrubbed, err := json.Marshal(scrubValue(value, fields))
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+func scrubValue(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if fields[strings.ToLower(key)] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = scrubValue(field, fields)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = scrubValue(v[i], fields)
+		}
+	}
+	return value
+}
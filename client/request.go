@@ -2,68 +2,217 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 func (c *ImmutaClient) Head(path, version string, query map[string]string) error {
-	return c.doRequest(http.MethodHead, path, version, query, nil, nil)
+	return c.HeadContext(context.Background(), path, version, query)
+}
+
+func (c *ImmutaClient) HeadContext(ctx context.Context, path, version string, query map[string]string) error {
+	return c.doRequest(ctx, http.MethodHead, path, version, query, nil, nil, true)
 }
 
 func (c *ImmutaClient) Get(path, version string, query map[string]string, output interface{}) error {
-	return c.doRequest(http.MethodGet, path, version, query, nil, output)
+	return c.GetContext(context.Background(), path, version, query, output)
+}
+
+func (c *ImmutaClient) GetContext(ctx context.Context, path, version string, query map[string]string, output interface{}) error {
+	return c.doRequest(ctx, http.MethodGet, path, version, query, nil, output, true)
 }
 
 func (c *ImmutaClient) Post(path, version string, params interface{}, output interface{}) error {
-	return c.doRequest(http.MethodPost, path, version, nil, params, output)
+	return c.PostContext(context.Background(), path, version, params, output)
+}
+
+func (c *ImmutaClient) PostContext(ctx context.Context, path, version string, params interface{}, output interface{}) error {
+	return c.doRequest(ctx, http.MethodPost, path, version, nil, params, output, false)
 }
 
 func (c *ImmutaClient) PostWithQuery(path, version string, params interface{}, query map[string]string, output interface{}) error {
-	return c.doRequest(http.MethodPost, path, version, query, params, output)
+	return c.PostWithQueryContext(context.Background(), path, version, params, query, output)
+}
+
+func (c *ImmutaClient) PostWithQueryContext(ctx context.Context, path, version string, params interface{}, query map[string]string, output interface{}) error {
+	return c.doRequest(ctx, http.MethodPost, path, version, query, params, output, false)
+}
+
+// Upsert is a POST to an endpoint that creates or updates in place, such as the /api/v2 endpoints,
+// so it is safe to retry on transient failures
+func (c *ImmutaClient) Upsert(path, version string, params interface{}, output interface{}) error {
+	return c.UpsertContext(context.Background(), path, version, params, output)
+}
+
+func (c *ImmutaClient) UpsertContext(ctx context.Context, path, version string, params interface{}, output interface{}) error {
+	return c.doRequest(ctx, http.MethodPost, path, version, nil, params, output, true)
+}
+
+func (c *ImmutaClient) UpsertWithQuery(path, version string, params interface{}, query map[string]string, output interface{}) error {
+	return c.UpsertWithQueryContext(context.Background(), path, version, params, query, output)
+}
+
+func (c *ImmutaClient) UpsertWithQueryContext(ctx context.Context, path, version string, params interface{}, query map[string]string, output interface{}) error {
+	return c.doRequest(ctx, http.MethodPost, path, version, query, params, output, true)
 }
 
 func (c *ImmutaClient) Put(path, version string, params interface{}, output interface{}) error {
-	return c.doRequest(http.MethodPut, path, version, nil, params, output)
+	return c.PutContext(context.Background(), path, version, params, output)
+}
+
+func (c *ImmutaClient) PutContext(ctx context.Context, path, version string, params interface{}, output interface{}) error {
+	return c.doRequest(ctx, http.MethodPut, path, version, nil, params, output, true)
 }
 
 func (c *ImmutaClient) Patch(path, version string, params interface{}, output interface{}) error {
-	return c.doRequest(http.MethodPatch, path, version, nil, params, output)
+	return c.PatchContext(context.Background(), path, version, params, output)
+}
+
+func (c *ImmutaClient) PatchContext(ctx context.Context, path, version string, params interface{}, output interface{}) error {
+	return c.doRequest(ctx, http.MethodPatch, path, version, nil, params, output, false)
 }
 
 func (c *ImmutaClient) Delete(path, version string, params interface{}, output interface{}) error {
-	return c.doRequest(http.MethodDelete, path, version, nil, params, output)
+	return c.DeleteContext(context.Background(), path, version, params, output)
+}
+
+func (c *ImmutaClient) DeleteContext(ctx context.Context, path, version string, params interface{}, output interface{}) error {
+	return c.doRequest(ctx, http.MethodDelete, path, version, nil, params, output, true)
 }
 
 func (c *ImmutaClient) DeleteWithQuery(path, version string, params interface{}, query map[string]string, output interface{}) error {
-	return c.doRequest(http.MethodDelete, path, version, query, params, output)
+	return c.DeleteWithQueryContext(context.Background(), path, version, params, query, output)
 }
 
-func (c *ImmutaClient) doRequest(method string, path string, version string, query map[string]string, params interface{}, output interface{}) error {
+func (c *ImmutaClient) DeleteWithQueryContext(ctx context.Context, path, version string, params interface{}, query map[string]string, output interface{}) error {
+	return c.doRequest(ctx, http.MethodDelete, path, version, query, params, output, true)
+}
 
-	var bodyReader io.Reader = nil
+func (c *ImmutaClient) doRequest(ctx context.Context, method string, path string, version string, query map[string]string, params interface{}, output interface{}, idempotent bool) error {
+	return c.doRequestWithTokens(ctx, c.tokens, method, path, version, query, params, output, idempotent)
+}
+
+// doRequestWithTokens authenticates with tokens from the given source, a nil source sends no bearer token
+func (c *ImmutaClient) doRequestWithTokens(ctx context.Context, tokens TokenSource, method string, path string, version string, query map[string]string, params interface{}, output interface{}, idempotent bool) error {
+	ctx = tflog.NewSubsystem(ctx, loggingSubsystem, tflog.WithRootFields())
+	ctx, span := startRequestSpan(ctx, method, path)
+
+	call := newRequestLog(method, path)
+	err := c.doRequestWithRetries(ctx, call, tokens, method, path, version, query, params, output, idempotent)
+	call.write(ctx, err)
+	endRequestSpan(span, call, err)
+	return err
+}
+
+// doRequestWithRetries sends a request until it succeeds, fails for good or runs out of retries,
+// recording the attempts in the call's log
+func (c *ImmutaClient) doRequestWithRetries(ctx context.Context, call *requestLog, tokens TokenSource, method string, path string, version string, query map[string]string, params interface{}, output interface{}, idempotent bool) error {
+
+	var body []byte = nil
 
 	if params != nil {
-		body, err := json.Marshal(params)
+		var err error
+		body, err = json.Marshal(params)
+		if err != nil {
+			return err
+		}
+	}
+
+	token, err := tokenFrom(ctx, tokens)
+	if err != nil {
+		return err
+	}
+
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		response, err := c.send(ctx, method, path, version, query, body, token)
+		call.observe(response)
+
+		if err != nil && ctx.Err() != nil {
+			// surface the cancellation itself rather than the wrapped transport error
+			return ctx.Err()
+		}
+
+		if attempt < c.Retry.MaxRetries && shouldRetry(method, idempotent, response, err) {
+			delay := c.Retry.retryDelay(attempt+1, response)
+			call.retry(ctx, delay, err)
+			if response != nil {
+				// drain the body so the connection can be reused
+				_, _ = io.Copy(io.Discard, response.Body)
+				_ = response.Body.Close()
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if err != nil {
 			return err
 		}
+
+		// an expired token is refreshed and the original request retried once
+		if response.StatusCode == http.StatusUnauthorized && !refreshed && tokens != nil {
+			changed, err := tokens.Invalidate(ctx, token)
+			if err != nil {
+				_ = response.Body.Close()
+				return err
+			}
+			if changed {
+				_, _ = io.Copy(io.Discard, response.Body)
+				_ = response.Body.Close()
+
+				refreshed = true
+				call.retry(ctx, 0, nil)
+				if token, err = tokens.Token(ctx); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		return c.handleResponse(response, output)
+	}
+}
+
+type operationTimeoutKey struct{}
+
+// withOperationTimeout lets requests made with the context run until the context's deadline rather than the
+// client's Timeout, for calls such as registering a data source that may legitimately take longer than an attempt
+// is otherwise allowed to. Without a deadline the client's Timeout still applies.
+func withOperationTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operationTimeoutKey{}, true)
+}
+
+// send performs a single attempt of a request, the body is re-read on every attempt
+func (c *ImmutaClient) send(ctx context.Context, method string, path string, version string, query map[string]string, body []byte, token string) (*http.Response, error) {
+	var bodyReader io.Reader = nil
+	if body != nil {
 		bodyReader = bytes.NewReader(body)
 	}
 
 	url := c.makeUrl(path)
-	request, err := http.NewRequest(method, url, bodyReader)
+	request, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for k, v := range c.DefaultHeaders {
 		request.Header.Set(k, v)
 	}
 
+	if token != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
 	if version != "" {
-		request.Header.Set("Accept", fmt.Sprintf("application/json; version=#{version}"))
+		request.Header.Set("Accept", fmt.Sprintf("application/json; version=%s", version))
 	}
 
 	if query != nil {
@@ -75,31 +224,60 @@ func (c *ImmutaClient) doRequest(method string, path string, version string, que
 		}
 	}
 
-	response, err := c.Client.Do(request)
-	defer c.Client.CloseIdleConnections()
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := c.Client
+	if _, ok := ctx.Deadline(); ok && ctx.Value(operationTimeoutKey{}) != nil {
+		// the operation's deadline bounds the attempt instead of the per-attempt timeout
+		httpClient.Timeout = 0
+	}
 
+	response, err := httpClient.Do(request)
 	if err != nil {
-		return err
+		release()
+		return nil, err
 	}
 
+	response.Body = &releasingBody{ReadCloser: response.Body, release: release}
+	return response, nil
+}
+
+func (c *ImmutaClient) handleResponse(response *http.Response, output interface{}) error {
 	if response.StatusCode >= 400 {
-		buf := new(bytes.Buffer)
-		_, err = buf.ReadFrom(response.Body)
+		defer func() {
+			_ = response.Body.Close()
+		}()
 
+		body, err := io.ReadAll(response.Body)
 		if err != nil {
 			return err
 		}
 
-		newStr := buf.String()
-		var err error
-		if response.StatusCode == 404 {
-			err = NewNotFoundError(newStr)
-		} else {
-			err = NewRequestError(response.StatusCode, newStr)
-		}
-
-		return err
+		return NewAPIError(response.StatusCode, body)
 	}
 
 	return c.unmarshall(response.Body, output)
 }
+
+func tokenFrom(ctx context.Context, tokens TokenSource) (string, error) {
+	if tokens == nil {
+		return "", nil
+	}
+	return tokens.Token(ctx)
+}
+
+// sleepContext waits for the given delay, returning early if the context is cancelled
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
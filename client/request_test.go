@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequest_cancelledContextAbortsInFlightRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := newTestClient(server, fastRetries(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	err := c.GetContext(ctx, "/slow", "", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRequest_cancelledContextStopsRetrying(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server, WithRetryConfig(RetryConfig{
+		MaxRetries: 5,
+		MinBackoff: time.Minute,
+		MaxBackoff: time.Minute,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.GetContext(ctx, "/unavailable", "", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("expected the retry wait to be interrupted by the deadline")
+	}
+}
+
+func TestRequest_timeoutBoundsEachAttempt(t *testing.T) {
+	release := make(chan struct{})
+	var attempts int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := newTestClient(server, fastRetries(1), WithRequestTimeout(20*time.Millisecond))
+
+	err := c.GetContext(context.Background(), "/slow", "", nil, nil)
+	if err == nil {
+		t.Fatal("expected the request to time out")
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected a timed out attempt to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRequest_dataSourceUpsertOutlastsRequestTimeout(t *testing.T) {
+	var attempts int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		// registering a large schema takes longer than a single attempt is allowed to
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"creating": ["snowflake"]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server, fastRetries(3), WithRequestTimeout(20*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	response, err := c.DataSources().Upsert(ctx, DataSourceInput{ConnectionKey: "snowflake"})
+	if err != nil {
+		t.Fatalf("expected the upsert to be bounded by the operation's deadline, got %s", err)
+	}
+	if len(response.Creating) != 1 {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
+
+func TestRequest_timedOutWriteIsNotRetried(t *testing.T) {
+	release := make(chan struct{})
+	var attempts int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := newTestClient(server, fastRetries(3), WithRequestTimeout(20*time.Millisecond))
+
+	err := c.UpsertContext(context.Background(), "/api/v2/purpose", "", map[string]string{"name": "slow"}, nil)
+	if err == nil {
+		t.Fatal("expected the request to time out")
+	}
+	if atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf("expected a timed out upsert not to be sent again, got %d attempts", attempts)
+	}
+}
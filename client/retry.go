@@ -0,0 +1,154 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 4
+	DefaultMinBackoff = 1 * time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryConfig controls how failed requests are retried
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt, 0 disables retrying
+	MaxRetries int
+	// MinBackoff is the base delay used for the first retry
+	MinBackoff time.Duration
+	// MaxBackoff caps both the computed backoff and any Retry-After value sent by the server
+	MaxBackoff time.Duration
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// retryableStatusCodes are the responses that indicate a transient failure of the Immuta API
+var retryableStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// idempotentMethods are retried by default, other methods must opt in per request
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return retryableStatusCodes[statusCode]
+}
+
+// isRetryableError reports whether a transport error is worth another attempt
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry attempt (starting at 1), using full jitter
+func (rc RetryConfig) backoff(attempt int) time.Duration {
+	minBackoff := rc.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultMinBackoff
+	}
+	maxBackoff := rc.MaxBackoff
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+
+	ceiling := float64(minBackoff) * math.Pow(2, float64(attempt-1))
+	if ceiling > float64(maxBackoff) {
+		ceiling = float64(maxBackoff)
+	}
+
+	// full jitter, but never less than the minimum backoff
+	jittered := time.Duration(rand.Int63n(int64(ceiling) + 1))
+	if jittered < minBackoff {
+		jittered = minBackoff
+	}
+	return jittered
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date
+func retryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	header := response.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// retryDelay picks the delay before the next attempt, preferring the server's Retry-After when present
+func (rc RetryConfig) retryDelay(attempt int, response *http.Response) time.Duration {
+	if delay, ok := retryAfter(response, time.Now()); ok {
+		if rc.MaxBackoff > 0 && delay > rc.MaxBackoff {
+			delay = rc.MaxBackoff
+		}
+		return delay
+	}
+	return rc.backoff(attempt)
+}
+
+// shouldRetry decides whether a failed attempt may be repeated. A 429 means the request was rejected before
+// being processed so it is always safe to retry, anything else is only retried for idempotent requests. A timed
+// out write is not retried even when idempotent, Immuta is likely still processing it and would only be sent the
+// same work again.
+func shouldRetry(method string, idempotent bool, response *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && method != http.MethodGet && method != http.MethodHead {
+			return false
+		}
+		return idempotent && isRetryableError(err)
+	}
+	if response.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent && isRetryableStatus(response.StatusCode)
+}
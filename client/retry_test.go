@@ -0,0 +1,137 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetries(maxRetries int) Option {
+	return WithRetryConfig(RetryConfig{
+		MaxRetries: maxRetries,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	})
+}
+
+// failingHandler fails the first `failures` requests with the given status then succeeds
+func failingHandler(failures int32, status int, calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}
+}
+
+func TestRetry_idempotentRequestIsRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewTLSServer(failingHandler(2, http.StatusServiceUnavailable, &calls))
+	defer server.Close()
+
+	c := newTestClient(server, fastRetries(3))
+
+	var output map[string]bool
+	if err := c.Get("/test", "", nil, &output); err != nil {
+		t.Fatalf("expected request to succeed after retries, got %s", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	if !output["ok"] {
+		t.Errorf("expected response body to be decoded")
+	}
+}
+
+func TestRetry_givesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewTLSServer(failingHandler(10, http.StatusBadGateway, &calls))
+	defer server.Close()
+
+	c := newTestClient(server, fastRetries(2))
+
+	if err := c.Get("/test", "", nil, nil); err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_postIsNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewTLSServer(failingHandler(1, http.StatusServiceUnavailable, &calls))
+	defer server.Close()
+
+	c := newTestClient(server, fastRetries(3))
+
+	if err := c.Post("/test", "", map[string]string{}, nil); err == nil {
+		t.Fatal("expected a non-idempotent POST to fail without retrying")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetry_upsertOptsIn(t *testing.T) {
+	var calls int32
+	server := httptest.NewTLSServer(failingHandler(1, http.StatusGatewayTimeout, &calls))
+	defer server.Close()
+
+	c := newTestClient(server, fastRetries(3))
+
+	if err := c.Upsert("/api/v2/test", "", map[string]string{}, nil); err != nil {
+		t.Fatalf("expected upsert to be retried, got %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetry_tooManyRequestsRetriesAnyMethod(t *testing.T) {
+	var calls int32
+	server := httptest.NewTLSServer(failingHandler(1, http.StatusTooManyRequests, &calls))
+	defer server.Close()
+
+	c := newTestClient(server, fastRetries(3))
+
+	if err := c.Post("/test", "", map[string]string{}, nil); err != nil {
+		t.Fatalf("expected POST to be retried after a 429, got %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetry_retryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]time.Duration{
+		"3": 3 * time.Second,
+		now.Add(10 * time.Second).Format(http.TimeFormat): 10 * time.Second,
+	}
+	for header, expected := range cases {
+		response := &http.Response{Header: http.Header{"Retry-After": []string{header}}}
+		delay, ok := retryAfter(response, now)
+		if !ok || delay != expected {
+			t.Errorf("Retry-After %q: expected %s, got %s (%t)", header, expected, delay, ok)
+		}
+	}
+
+	if _, ok := retryAfter(&http.Response{Header: http.Header{}}, now); ok {
+		t.Error("expected no delay without a Retry-After header")
+	}
+}
+
+func TestRetry_backoffIsCapped(t *testing.T) {
+	rc := RetryConfig{MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt := 1; attempt < 10; attempt++ {
+		delay := rc.backoff(attempt)
+		if delay < rc.MinBackoff || delay > rc.MaxBackoff {
+			t.Errorf("attempt %d: delay %s outside [%s, %s]", attempt, delay, rc.MinBackoff, rc.MaxBackoff)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+// API groups the typed services of the Immuta API. Resources depend on the services rather than
+// on ImmutaClient so they can be exercised against fakes.
+type API interface {
+	FeatureChecker
+	Purposes() PurposesService
+	Projects() ProjectsService
+	Tags() TagsService
+	BimUsers() BimUsersService
+	BimGroups() BimGroupsService
+	BimAttributes() BimAttributesService
+	DataSources() DataSourcesService
+}
+
+// FeatureChecker tells whether the tenant may be too old for a feature
+type FeatureChecker interface {
+	CheckFeature(feature Feature) error
+}
+
+var _ API = &ImmutaClient{}
+
+func (c *ImmutaClient) Purposes() PurposesService {
+	return &purposesService{client: c}
+}
+
+func (c *ImmutaClient) Projects() ProjectsService {
+	return &projectsService{client: c}
+}
+
+func (c *ImmutaClient) Tags() TagsService {
+	return &tagsService{client: c}
+}
+
+func (c *ImmutaClient) BimUsers() BimUsersService {
+	return &bimUsersService{client: c}
+}
+
+func (c *ImmutaClient) BimGroups() BimGroupsService {
+	return &bimGroupsService{client: c}
+}
+
+func (c *ImmutaClient) BimAttributes() BimAttributesService {
+	return &bimAttributesService{client: c}
+}
+
+func (c *ImmutaClient) DataSources() DataSourcesService {
+	return &dataSourcesService{client: c}
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestServices_upsertIgnoresEstimatedRelease(t *testing.T) {
+	var calls int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	c.Release = version.Must(version.NewVersion("2022.1.0"))
+
+	if _, err := c.Purposes().Upsert(context.Background(), PurposeInput{}); err != nil {
+		t.Errorf("expected purposes to be upserted, got %v", err)
+	}
+	if _, err := c.Projects().Upsert(context.Background(), ProjectInput{}); err != nil {
+		t.Errorf("expected projects to be upserted, got %v", err)
+	}
+	if _, err := c.DataSources().Upsert(context.Background(), DataSourceInput{}); err != nil {
+		t.Errorf("expected data sources to be upserted, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected the estimated releases not to hold requests back, got %d requests", calls)
+	}
+}
+
+func TestServices_exists(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bim/group/1", "/api/v2/data/present":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	ctx := context.Background()
+
+	cases := map[string]func() (bool, error){
+		"group 1":                func() (bool, error) { return c.BimGroups().Exists(ctx, "1") },
+		"connection present":     func() (bool, error) { return c.DataSources().Exists(ctx, "present") },
+		"group 2":                func() (bool, error) { return c.BimGroups().Exists(ctx, "2") },
+		"connection not present": func() (bool, error) { return c.DataSources().Exists(ctx, "absent") },
+	}
+	expected := map[string]bool{"group 1": true, "connection present": true}
+
+	for name, exists := range cases {
+		found, err := exists()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+		if found != expected[name] {
+			t.Errorf("%s: expected exists to be %t", name, expected[name])
+		}
+	}
+}
+
+func TestServices_getTagSearchesByName(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "name": "PII.Email"}, {"id": 2, "name": "PII"}]`))
+	}))
+	defer server.Close()
+
+	tags := newTestClient(server).Tags()
+
+	tag, err := tags.Get(context.Background(), "PII")
+	if err != nil || tag == nil || tag.Id != 2 {
+		t.Errorf("expected the exact match, got %+v (%v)", tag, err)
+	}
+
+	tag, err = tags.Get(context.Background(), "Missing")
+	if err != nil || tag != nil {
+		t.Errorf("expected no tag, got %+v (%v)", tag, err)
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TagsService manages tags, which can only be looked up by searching for their name
+type TagsService interface {
+	Create(ctx context.Context, tag TagInput) (*TagCreateResponse, error)
+	// Get returns nil if the tag does not exist
+	Get(ctx context.Context, name string) (*TagList, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type tagsService struct {
+	client *ImmutaClient
+}
+
+func (s *tagsService) Create(ctx context.Context, tag TagInput) (*TagCreateResponse, error) {
+	responses := make([]TagCreateResponse, 0)
+	if err := s.client.PostContext(ctx, "/tag", "", tag, &responses); err != nil {
+		return nil, err
+	}
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("no response from create tag")
+	}
+	return &responses[0], nil
+}
+
+func (s *tagsService) Get(ctx context.Context, name string) (*TagList, error) {
+	// have to search for the tag because the API doesn't support getting a tag by name/id
+	paginator := NewPaginator[TagList](s.client, "/tag", "", map[string]string{"searchText": name}, "")
+	for paginator.Next(ctx) {
+		for _, tag := range paginator.Page() {
+			if tag.Name == name {
+				return &tag, nil
+			}
+		}
+	}
+
+	return nil, paginator.Err()
+}
+
+func (s *tagsService) Delete(ctx context.Context, name string) error {
+	return s.client.DeleteContext(ctx, fmt.Sprintf("/tag/%s", name), "", nil, nil)
+}
+
+// Domain specific types
+
+type TagInput struct {
+	Tags     []TagSingular `json:"tags"`
+	*RootTag `json:"rootTag,omitempty"`
+}
+
+type TagSingular struct {
+	Name string `json:"name"`
+}
+
+type Tag struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Source      string `json:"source"`
+	Context     string `json:"context"`
+	AddedBy     int    `json:"addedBy"`
+	Deleted     bool   `json:"deleted"`
+}
+
+type RootTag struct {
+	Name            string `json:"name,omitempty"`
+	DeleteHierarchy bool   `json:"deleteHierarchy,omitempty"`
+}
+
+type TagCreateResponse struct {
+	Id            int       `json:"id"`
+	Name          string    `json:"name"`
+	Source        string    `json:"source"`
+	Deleted       bool      `json:"deleted"`
+	SystemCreated bool      `json:"systemCreated"`
+	CreatedBy     int       `json:"createdBy"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type TagList struct {
+	Name          string `json:"name"`
+	HasLeafNodes  bool   `json:"hasLeafNodes"`
+	Source        string `json:"source"`
+	Id            int    `json:"id"`
+	Deleted       bool   `json:"deleted"`
+	SystemCreated bool   `json:"systemCreated"`
+	DisplayName   string `json:"displayName"`
+}
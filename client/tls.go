@@ -0,0 +1,104 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	SchemeHTTPS = "https"
+	SchemeHTTP  = "http"
+)
+
+// TLSOptions describes how the client verifies the tenant and authenticates itself with a certificate
+type TLSOptions struct {
+	// CACertPEM holds PEM encoded certificates trusted in addition to the system pool
+	CACertPEM []byte
+	// ClientCertPEM and ClientKeyPEM enable mutual TLS, both must be set together
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+	// InsecureSkipVerify disables verification of the tenant's certificate, only use it for testing
+	InsecureSkipVerify bool
+}
+
+// NewTLSConfig builds the tls.Config described by the options
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if len(options.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(options.CACertPEM) {
+			return nil, errors.New("no certificates could be parsed from the CA certificate PEM")
+		}
+		config.RootCAs = pool
+	}
+
+	if len(options.ClientCertPEM) > 0 || len(options.ClientKeyPEM) > 0 {
+		if len(options.ClientCertPEM) == 0 || len(options.ClientKeyPEM) == 0 {
+			return nil, errors.New("a client certificate and key must be set together")
+		}
+		certificate, err := tls.X509KeyPair(options.ClientCertPEM, options.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the tenant
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *ImmutaClient) {
+		c.tlsConfig = config
+	}
+}
+
+// WithProxyURL sends every request through the given proxy instead of the one from the environment
+func WithProxyURL(proxy *url.URL) Option {
+	return func(c *ImmutaClient) {
+		c.proxy = http.ProxyURL(proxy)
+	}
+}
+
+// WithScheme changes the scheme used to reach the tenant, e.g. http for a local stand-in
+func WithScheme(scheme string) Option {
+	return func(c *ImmutaClient) {
+		c.Scheme = scheme
+	}
+}
+
+// WithBasePath prefixes every request path, for tenants served below a path on a shared host
+func WithBasePath(basePath string) Option {
+	return func(c *ImmutaClient) {
+		c.BasePath = normaliseBasePath(basePath)
+	}
+}
+
+// ValidateScheme returns an error unless scheme is http or https
+func ValidateScheme(scheme string) error {
+	if scheme != SchemeHTTPS && scheme != SchemeHTTP {
+		return fmt.Errorf("scheme must be %q or %q, got %q", SchemeHTTPS, SchemeHTTP, scheme)
+	}
+	return nil
+}
+
+// normaliseBasePath gives the path a single leading slash and no trailing one
+func normaliseBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTLS_customCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	untrusted := NewClient(host, "token", "test")
+	if err := untrusted.GetContext(context.Background(), "/test", "", nil, nil); err == nil {
+		t.Fatal("expected the test server's certificate to be rejected without its CA")
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	tlsConfig, err := NewTLSConfig(TLSOptions{CACertPEM: caPEM})
+	if err != nil {
+		t.Fatalf("unexpected error building the TLS config: %s", err)
+	}
+
+	trusted := NewClient(host, "token", "test", WithTLSConfig(tlsConfig))
+	if err := trusted.GetContext(context.Background(), "/test", "", nil, nil); err != nil {
+		t.Errorf("expected the custom CA to be trusted, got %s", err)
+	}
+}
+
+func TestTLS_invalidOptions(t *testing.T) {
+	cases := map[string]TLSOptions{
+		"bad CA":              {CACertPEM: []byte("not a certificate")},
+		"key without cert":    {ClientKeyPEM: []byte("key")},
+		"unparseable keypair": {ClientCertPEM: []byte("cert"), ClientKeyPEM: []byte("key")},
+	}
+	for name, options := range cases {
+		if _, err := NewTLSConfig(options); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestTLS_schemeAndBasePath(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}))
+	defer server.Close()
+
+	c := NewClient(strings.TrimPrefix(server.URL, "http://"), "token", "test", WithScheme(SchemeHTTP), WithBasePath("immuta/"))
+
+	if err := c.GetContext(context.Background(), "/bim/user", "", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if path != "/immuta/bim/user" {
+		t.Errorf("unexpected path %q", path)
+	}
+}
+
+func TestTLS_proxy(t *testing.T) {
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	c := NewClient("immuta.internal", "token", "test", WithScheme(SchemeHTTP), WithProxyURL(proxyURL))
+
+	if err := c.GetContext(context.Background(), "/test", "", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if requested != "http://immuta.internal/test" {
+		t.Errorf("expected the request to go through the proxy, got %q", requested)
+	}
+}
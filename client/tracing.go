@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of the client, they are only recorded once a tracer provider has been installed
+const tracerName = "github.com/immuta/terraform-provider-immuta/client"
+
+// startRequestSpan starts a span covering a client call, including its retries
+func startRequestSpan(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "HTTP "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(method),
+			semconv.HTTPTargetKey.String(path),
+		),
+	)
+}
+
+// endRequestSpan records the outcome of the call on its span and ends it
+func endRequestSpan(span trace.Span, call *requestLog, err error) {
+	span.SetAttributes(attribute.Int("immuta.retries", call.retries))
+	if call.status != 0 {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(call.status))
+	}
+	if call.requestId != "" {
+		span.SetAttributes(attribute.String("immuta.request_id", call.requestId))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
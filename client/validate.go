@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+// currentUserPath returns the user the request is authenticated as, it is the cheapest authenticated call
+const currentUserPath = "/bim/rpc/user/current"
+
+// CurrentUser is the Immuta user the client is authenticated as
+type CurrentUser struct {
+	Id     int    `json:"id"`
+	UserId string `json:"userid"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// ConnectionError explains why the tenant could not be reached with the configured credentials
+type ConnectionError struct {
+	// Summary is a short description of the failure
+	Summary string
+	// Hint suggests which setting to look at
+	Hint string
+	Err  error
+}
+
+func (err *ConnectionError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Summary, err.Err)
+}
+
+func (err *ConnectionError) Unwrap() error {
+	return err.Err
+}
+
+// Validate makes a single authenticated call to check the host and credentials,
+// returning a ConnectionError describing the failure
+func (c *ImmutaClient) Validate(ctx context.Context) (*CurrentUser, error) {
+	user := CurrentUser{}
+	if err := c.GetContext(ctx, currentUserPath, "", nil, &user); err != nil {
+		return nil, c.classifyConnectionError(err)
+	}
+	return &user, nil
+}
+
+func (c *ImmutaClient) classifyConnectionError(err error) *ConnectionError {
+	var apiErr *APIError
+	var dnsErr *net.DNSError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalidCertificate x509.CertificateInvalidError
+
+	switch {
+	case IsUnauthorized(err):
+		return &ConnectionError{
+			Summary: "Immuta rejected the credentials",
+			Hint:    "Check that the token, API key or username and password are valid and have not expired.",
+			Err:     err,
+		}
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden:
+		return &ConnectionError{
+			Summary: "The credentials are not allowed to use the Immuta API",
+			Hint:    "Check the permissions of the user the credentials belong to.",
+			Err:     err,
+		}
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		return &ConnectionError{
+			Summary: fmt.Sprintf("%s does not look like an Immuta tenant", c.Host),
+			Hint:    "Check host and base_path.",
+			Err:     err,
+		}
+	case errors.As(err, &dnsErr):
+		return &ConnectionError{
+			Summary: fmt.Sprintf("Could not resolve the Immuta host %s", c.Host),
+			Hint:    "Check that host is the fully qualified domain name of the tenant, without a scheme or path.",
+			Err:     err,
+		}
+	case errors.As(err, &unknownAuthority), errors.As(err, &hostname), errors.As(err, &invalidCertificate):
+		return &ConnectionError{
+			Summary: fmt.Sprintf("Could not verify the TLS certificate of %s", c.Host),
+			Hint:    "Set ca_cert_pem or ca_cert_file when the tenant uses an internal CA.",
+			Err:     err,
+		}
+	case strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		// net/http only exposes this as a plain error before Go 1.21
+		return &ConnectionError{
+			Summary: fmt.Sprintf("%s did not answer with TLS", c.Host),
+			Hint:    "Set scheme to \"http\" if the tenant is served over plain HTTP.",
+			Err:     err,
+		}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return &ConnectionError{
+			Summary: fmt.Sprintf("Could not connect to %s", c.Host),
+			Hint:    "Check host, including any port, and proxy_url.",
+			Err:     err,
+		}
+	}
+
+	return &ConnectionError{
+		Summary: "Could not validate the Immuta credentials",
+		Hint:    "Set skip_credentials_validation to plan without reaching Immuta.",
+		Err:     err,
+	}
+}
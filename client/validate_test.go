@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidate_currentUser(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != currentUserPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 2, "userid": "ci@example.com", "name": "CI"}`))
+	}))
+	defer server.Close()
+
+	user, err := newTestClient(server).Validate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if user.UserId != "ci@example.com" {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestValidate_classifiesFailures(t *testing.T) {
+	unauthorized := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+
+	cases := map[string]struct {
+		client  *ImmutaClient
+		summary string
+	}{
+		"unauthorized": {
+			client:  newTestClient(unauthorized, fastRetries(0)),
+			summary: "rejected the credentials",
+		},
+		"untrusted certificate": {
+			client:  NewClient(strings.TrimPrefix(unauthorized.URL, "https://"), "token", "test", fastRetries(0)),
+			summary: "Could not verify the TLS certificate",
+		},
+		"plain http": {
+			client:  NewClient(strings.TrimPrefix(plain.URL, "http://"), "token", "test", fastRetries(0)),
+			summary: "did not answer with TLS",
+		},
+		"unknown host": {
+			client:  NewClient("immuta.invalid", "token", "test", fastRetries(0)),
+			summary: "Could not resolve",
+		},
+	}
+
+	for name, tc := range cases {
+		_, err := tc.client.Validate(context.Background())
+
+		var connectionErr *ConnectionError
+		if !errors.As(err, &connectionErr) {
+			t.Errorf("%s: expected a ConnectionError, got %v", name, err)
+			continue
+		}
+		if !strings.Contains(connectionErr.Summary, tc.summary) {
+			t.Errorf("%s: expected %q in the summary, got %q", name, tc.summary, connectionErr.Summary)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+)
+
+// releasePath is a cheap endpoint returning the release of the tenant. It is not part of Immuta's published API
+// reference, so a tenant that does not answer it is not gated at all, see Supports.
+const releasePath = "/version"
+
+// Feature is a part of the Immuta API that only exists from a given release onwards. Every endpoint is sent a single
+// payload shape: choosing between V1 and V2 shapes per release needs the releases that introduced the V2 endpoints,
+// which are not confirmed, so features are only checked to warn and never hold a request back.
+type Feature struct {
+	Name string
+	// MinRelease is the oldest release the feature is enabled for. Immuta's API reference does not state when the
+	// V2 endpoints were introduced, the releases below are estimates to be confirmed against Immuta's release notes.
+	MinRelease string
+}
+
+var (
+	FeatureV2Data = Feature{
+		Name:       "V2 data source registration (/api/v2/data)",
+		MinRelease: "2022.2.0",
+	}
+	FeatureV2Purpose = Feature{
+		Name:       "V2 purpose upsert (/api/v2/purpose)",
+		MinRelease: "2023.1.0",
+	}
+	FeatureV2Project = Feature{
+		Name:       "V2 project upsert (/api/v2/project)",
+		MinRelease: "2023.1.0",
+	}
+)
+
+// UnsupportedFeatureError reports that the tenant may be too old for a feature
+type UnsupportedFeatureError struct {
+	Feature Feature
+	Release *version.Version
+}
+
+func (err *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s requires Immuta %s or later, the tenant is running %s", err.Feature.Name, err.Feature.MinRelease, err.Release)
+}
+
+type releaseResponse struct {
+	Version string `json:"version"`
+}
+
+// DetectRelease asks the tenant for its release once, later calls are no-ops
+func (c *ImmutaClient) DetectRelease(ctx context.Context) error {
+	if c.Release != nil {
+		return nil
+	}
+
+	response := releaseResponse{}
+	if err := c.GetContext(ctx, releasePath, "", nil, &response); err != nil {
+		return err
+	}
+
+	release, err := version.NewVersion(response.Version)
+	if err != nil {
+		return fmt.Errorf("could not parse Immuta release %q: %w", response.Version, err)
+	}
+
+	c.Release = release
+	return nil
+}
+
+// Supports reports whether the tenant looks new enough for a feature. When the release could not be detected
+// every feature is assumed to be supported and the API is left to reject the request.
+func (c *ImmutaClient) Supports(feature Feature) bool {
+	if c.Release == nil {
+		return true
+	}
+	minRelease, err := version.NewVersion(feature.MinRelease)
+	if err != nil {
+		return true
+	}
+	return c.Release.Core().GreaterThanOrEqual(minRelease)
+}
+
+// CheckFeature returns an UnsupportedFeatureError if the tenant may be too old for a feature. It is advisory, the
+// releases of features are estimates so callers warn and still send the request.
+func (c *ImmutaClient) CheckFeature(feature Feature) error {
+	if c.Supports(feature) {
+		return nil
+	}
+	return &UnsupportedFeatureError{Feature: feature, Release: c.Release}
+}
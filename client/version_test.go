@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestVersion_detectReleaseOnce(t *testing.T) {
+	var calls int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != releasePath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"version": "2022.4.1"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	for i := 0; i < 2; i++ {
+		if err := c.DetectRelease(context.Background()); err != nil {
+			t.Fatalf("unexpected error detecting release: %s", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the release to be fetched once, got %d calls", calls)
+	}
+	if c.Release.String() != "2022.4.1" {
+		t.Errorf("unexpected release %s", c.Release)
+	}
+
+	if !c.Supports(FeatureV2Data) {
+		t.Errorf("expected %s to be supported", FeatureV2Data.Name)
+	}
+	if c.Supports(FeatureV2Purpose) {
+		t.Errorf("expected %s not to be supported", FeatureV2Purpose.Name)
+	}
+
+	var unsupported *UnsupportedFeatureError
+	if err := c.CheckFeature(FeatureV2Project); !errors.As(err, &unsupported) {
+		t.Errorf("expected an UnsupportedFeatureError, got %v", err)
+	}
+}
+
+func TestVersion_unknownReleaseSupportsEverything(t *testing.T) {
+	c := NewClient("localhost", "token", "test")
+
+	if err := c.CheckFeature(FeatureV2Purpose); err != nil {
+		t.Errorf("expected every feature to be allowed before detection, got %s", err)
+	}
+}
+
+func TestVersion_v2RequestsSendNoVersion(t *testing.T) {
+	var accept []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = append(accept, r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	if _, err := c.DataSources().Upsert(context.Background(), DataSourceInput{ConnectionKey: "snowflake"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DataSources().Delete(context.Background(), "snowflake"); err != nil {
+		t.Fatal(err)
+	}
+	for _, header := range accept {
+		if strings.Contains(header, "version=") {
+			t.Errorf("expected V2 requests to be sent without a payload version, got Accept %q", header)
+		}
+	}
+}
+
+func TestVersion_acceptHeader(t *testing.T) {
+	var accept string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	if err := c.Get("/test", "2", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if accept != "application/json; version=2" {
+		t.Errorf("unexpected Accept header %q", accept)
+	}
+}
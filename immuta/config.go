@@ -1,16 +1,18 @@
 package immuta
 
 import (
+	"context"
 	"fmt"
 	"github.com/immuta/terraform-provider-immuta/client"
 )
 
 type Config struct {
-	APIToken string
-	Host     string
+	APIToken                  string
+	Host                      string
+	SkipCredentialsValidation bool
 }
 
-func (config *Config) ImmutaClient() (interface{}, error) {
+func (config *Config) ImmutaClient(ctx context.Context) (interface{}, error) {
 	if config.Host == "" {
 		return nil, fmt.Errorf("no Host set")
 	}
@@ -22,8 +24,11 @@ func (config *Config) ImmutaClient() (interface{}, error) {
 
 	immutaClient := client.NewClient(config.Host, config.APIToken, userAgent)
 
-	// todo validate client once low cost API call is available
-	err := error(nil)
+	if !config.SkipCredentialsValidation {
+		if _, err := immutaClient.Validate(ctx); err != nil {
+			return nil, err
+		}
+	}
 
-	return immutaClient, err
+	return immutaClient, nil
 }
@@ -0,0 +1,513 @@
+package immuta
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"sort"
+	"strings"
+)
+
+// Authentication methods of the handler attributes, each handler supports a subset of them. Only the password
+// method is supported until the authenticationMethod values Immuta expects for the others are confirmed.
+const (
+	authenticationPassword = "password"
+)
+
+// authenticationMethod is how Immuta authenticates to the data platform and the attributes it needs for it
+type authenticationMethod struct {
+	// api is the authenticationMethod sent to Immuta, it is left out when empty
+	api      string
+	required []string
+	// exactly one of oneOf must be set
+	oneOf    []string
+	optional []string
+	// computed attributes are set by the provider rather than configured
+	computed []string
+}
+
+// attributes are the configurable attributes of the method
+func (m authenticationMethod) attributes() []string {
+	attributes := append([]string{}, m.required...)
+	attributes = append(attributes, m.oneOf...)
+	return append(attributes, m.optional...)
+}
+
+func (m authenticationMethod) uses(attribute string) bool {
+	for _, name := range m.attributes() {
+		if name == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+var authenticationMethods = map[string]authenticationMethod{
+	authenticationPassword: {
+		required: []string{"username"},
+		oneOf:    []string{"password", "password_file"},
+		optional: []string{"password_version"},
+		computed: []string{"password_hmac"},
+	},
+}
+
+// connectionAttribute is an attribute of a handler and the field of the V2 API's connection it is sent as
+type connectionAttribute struct {
+	schema schema.Attribute
+	// secret attributes are never returned by Immuta, the state keeps what Terraform last sent
+	secret  bool
+	toAPI   func(value attr.Value, connection *client.DataSourceConnection) error
+	fromAPI func(connection client.DataSourceConnection) attr.Value
+}
+
+// connectionHandler is one of the attributes of connection_details, named after the data platform
+type connectionHandler struct {
+	// name is the handler Immuta registers the connection with
+	name        string
+	description string
+	// authenticationMethods are the methods the platform supports, the first one is the default
+	authenticationMethods []string
+	attributes            map[string]connectionAttribute
+}
+
+// connectionHandlers are keyed by the attribute name of the handler in connection_details
+var connectionHandlers = map[string]connectionHandler{
+	"snowflake": newConnectionHandler(
+		"Snowflake",
+		"Connect to Snowflake.",
+		[]string{authenticationPassword},
+		hostAttributes(443),
+		map[string]connectionAttribute{
+			"warehouse": stringConnectionAttribute(schema.StringAttribute{
+				Required:    true,
+				Description: "The warehouse used to ingest the tables.",
+				Validators:  []validator.String{stringNotEmpty()},
+			}, func(connection *client.DataSourceConnection) *string { return &connection.Warehouse }),
+		},
+	),
+	"databricks": newConnectionHandler(
+		"Databricks",
+		"Connect to Databricks.",
+		[]string{authenticationPassword},
+		hostAttributes(443),
+		map[string]connectionAttribute{
+			"http_path": stringConnectionAttribute(schema.StringAttribute{
+				Required:    true,
+				Description: "The HTTP path of the cluster or SQL warehouse used to ingest the tables, e.g. \"/sql/1.0/warehouses/abc123\".",
+				Validators:  []validator.String{stringHasPrefix("/")},
+			}, func(connection *client.DataSourceConnection) *string { return &connection.HttpPath }),
+		},
+	),
+	"redshift": newConnectionHandler(
+		"Redshift",
+		"Connect to Amazon Redshift.",
+		[]string{authenticationPassword},
+		hostAttributes(5439),
+		sslAttribute(),
+	),
+	"postgresql": newConnectionHandler(
+		"PostgreSQL",
+		"Connect to PostgreSQL.",
+		[]string{authenticationPassword},
+		hostAttributes(5432),
+		sslAttribute(),
+	),
+}
+
+func newConnectionHandler(name, description string, methods []string, attributes ...map[string]connectionAttribute) connectionHandler {
+	handler := connectionHandler{
+		name:                  name,
+		description:           description,
+		authenticationMethods: methods,
+		attributes: map[string]connectionAttribute{
+			"authentication_method": authenticationMethodAttribute(methods),
+		},
+	}
+
+	for _, set := range attributes {
+		for attributeName, attribute := range set {
+			handler.attributes[attributeName] = attribute
+		}
+	}
+	credentials := credentialAttributes()
+	for _, method := range methods {
+		for _, attributeName := range append(authenticationMethods[method].attributes(), authenticationMethods[method].computed...) {
+			handler.attributes[attributeName] = credentials[attributeName]
+		}
+	}
+
+	return handler
+}
+
+func (h connectionHandler) supports(method string) bool {
+	for _, supported := range h.authenticationMethods {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeTypes are the types of the handler's attributes in the state
+func (h connectionHandler) attributeTypes() map[string]attr.Type {
+	attributeTypes := map[string]attr.Type{}
+	for name, attribute := range h.attributes {
+		attributeTypes[name] = attribute.schema.GetType()
+	}
+	return attributeTypes
+}
+
+// connectionDetailsAttributes is the schema of connection_details, an optional attribute per handler
+func connectionDetailsAttributes() map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for name, handler := range connectionHandlers {
+		nested := map[string]schema.Attribute{}
+		for attributeName, attribute := range handler.attributes {
+			nested[attributeName] = attribute.schema
+		}
+		attributes[name] = schema.SingleNestedAttribute{
+			Optional:    true,
+			Description: handler.description,
+			Attributes:  nested,
+		}
+	}
+	return attributes
+}
+
+func connectionDetailsAttributeTypes() map[string]attr.Type {
+	attributeTypes := map[string]attr.Type{}
+	for name, handler := range connectionHandlers {
+		attributeTypes[name] = types.ObjectType{AttrTypes: handler.attributeTypes()}
+	}
+	return attributeTypes
+}
+
+func handlerNames() []string {
+	names := make([]string, 0, len(connectionHandlers))
+	for name := range connectionHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateConnectionDetails checks that exactly one handler is configured and that it has the attributes its
+// authentication method needs, and no others
+func validateConnectionDetails(connection types.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var configured []string
+	for _, name := range handlerNames() {
+		if !connection.Attributes()[name].IsNull() {
+			configured = append(configured, name)
+		}
+	}
+	switch {
+	case len(configured) == 0:
+		diags.AddAttributeError(
+			path.Root("connection_details"),
+			"Missing connection handler",
+			fmt.Sprintf("Set one of %s in connection_details.", strings.Join(handlerNames(), ", ")),
+		)
+		return diags
+	case len(configured) > 1:
+		diags.AddAttributeError(
+			path.Root("connection_details"),
+			"Conflicting connection handlers",
+			fmt.Sprintf("Only one handler can be set in connection_details, got %s.", strings.Join(configured, ", ")),
+		)
+		return diags
+	}
+
+	name := configured[0]
+	handlerObject, _ := connection.Attributes()[name].(types.Object)
+	if handlerObject.IsUnknown() {
+		return diags
+	}
+	handler := connectionHandlers[name]
+	handlerPath := path.Root("connection_details").AtName(name)
+
+	methodValue, _ := handlerObject.Attributes()["authentication_method"].(types.String)
+	if methodValue.IsUnknown() {
+		return diags
+	}
+	methodName := handler.authenticationMethods[0]
+	if !methodValue.IsNull() {
+		methodName = methodValue.ValueString()
+	}
+	if !handler.supports(methodName) {
+		// reported by the attribute's validator
+		return diags
+	}
+	method := authenticationMethods[methodName]
+
+	for _, attributeName := range method.required {
+		if handlerObject.Attributes()[attributeName].IsNull() {
+			diags.AddAttributeError(
+				handlerPath.AtName(attributeName),
+				"Missing connection attribute",
+				fmt.Sprintf("%s is required to authenticate to %s with %s.", attributeName, handler.name, methodName),
+			)
+		}
+	}
+	if len(method.oneOf) > 0 && len(setAttributes(handlerObject, method.oneOf)) == 0 {
+		diags.AddAttributeError(
+			handlerPath.AtName(method.oneOf[0]),
+			"Missing connection attribute",
+			fmt.Sprintf("One of %s is required to authenticate to %s with %s.", strings.Join(method.oneOf, ", "), handler.name, methodName),
+		)
+	}
+	if set := setAttributes(handlerObject, method.oneOf); len(set) > 1 {
+		diags.AddAttributeError(
+			handlerPath.AtName(set[1]),
+			"Conflicting connection attributes",
+			fmt.Sprintf("Only one of %s can be set, got %s.", strings.Join(method.oneOf, ", "), strings.Join(set, ", ")),
+		)
+	}
+	for _, other := range handler.authenticationMethods {
+		for _, attributeName := range authenticationMethods[other].attributes() {
+			if !method.uses(attributeName) && !handlerObject.Attributes()[attributeName].IsNull() {
+				diags.AddAttributeError(
+					handlerPath.AtName(attributeName),
+					"Unexpected connection attribute",
+					fmt.Sprintf("%s is not used to authenticate with %s, remove it or change authentication_method.", attributeName, methodName),
+				)
+			}
+		}
+	}
+
+	return diags
+}
+
+// setAttributes returns the attributes of a handler among names that are set
+func setAttributes(handlerObject types.Object, names []string) []string {
+	var set []string
+	for _, attributeName := range names {
+		if !handlerObject.Attributes()[attributeName].IsNull() {
+			set = append(set, attributeName)
+		}
+	}
+	return set
+}
+
+// connectionFromResourceData converts the handler set in connection_details into the connection sent to Immuta
+func connectionFromResourceData(connection types.Object) (client.DataSourceConnection, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	apiConnection := client.DataSourceConnection{}
+
+	for _, name := range handlerNames() {
+		handlerObject, _ := connection.Attributes()[name].(types.Object)
+		if handlerObject.IsNull() || handlerObject.IsUnknown() {
+			continue
+		}
+
+		handler := connectionHandlers[name]
+		handlerPath := path.Root("connection_details").AtName(name)
+		apiConnection.Handler = handler.name
+		attributeNames := make([]string, 0, len(handler.attributes))
+		for attributeName := range handler.attributes {
+			attributeNames = append(attributeNames, attributeName)
+		}
+		sort.Strings(attributeNames)
+		for _, attributeName := range attributeNames {
+			value := handlerObject.Attributes()[attributeName]
+			if value == nil || value.IsNull() || value.IsUnknown() {
+				continue
+			}
+			if err := handler.attributes[attributeName].toAPI(value, &apiConnection); err != nil {
+				diags.AddAttributeError(handlerPath.AtName(attributeName), "Invalid connection attribute", err.Error())
+			}
+		}
+		return apiConnection, diags
+	}
+
+	diags.AddAttributeError(path.Root("connection_details"), "Missing connection handler",
+		fmt.Sprintf("Set one of %s in connection_details.", strings.Join(handlerNames(), ", ")))
+	return apiConnection, diags
+}
+
+// connectionFromAPI converts a connection reported by Immuta into connection_details, including the secrets it
+// holds. Immuta leaves the secrets out when reading a connection, refreshes keep the prior ones with withPriorSecrets.
+func connectionFromAPI(connection client.DataSourceConnection) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	handlers := map[string]attr.Value{}
+	found := false
+	for name, handler := range connectionHandlers {
+		if !strings.EqualFold(handler.name, connection.Handler) {
+			handlers[name] = types.ObjectNull(handler.attributeTypes())
+			continue
+		}
+
+		attributes := map[string]attr.Value{}
+		for attributeName, attribute := range handler.attributes {
+			attributes[attributeName] = attribute.fromAPI(connection)
+		}
+		handlers[name] = types.ObjectValueMust(handler.attributeTypes(), attributes)
+		found = true
+	}
+
+	if !found {
+		diags.AddError(
+			"Unsupported connection handler",
+			fmt.Sprintf("Immuta reports the %q handler, which the provider does not support. Supported handlers are %s.", connection.Handler, strings.Join(handlerNames(), ", ")),
+		)
+		return types.ObjectNull(connectionDetailsAttributeTypes()), diags
+	}
+	return types.ObjectValueMust(connectionDetailsAttributeTypes(), handlers), diags
+}
+
+// withPriorSecrets restores the secrets Immuta does not return from the prior state of connection_details
+func withPriorSecrets(ctx context.Context, refreshed, prior types.Object) types.Object {
+	handlers := map[string]attr.Value{}
+	for name, value := range refreshed.Attributes() {
+		handlerObject, _ := value.(types.Object)
+		if handlerObject.IsNull() {
+			handlers[name] = value
+			continue
+		}
+
+		priorHandler, _ := prior.Attributes()[name].(types.Object)
+		attributes := map[string]attr.Value{}
+		for attributeName, attributeValue := range handlerObject.Attributes() {
+			if connectionHandlers[name].attributes[attributeName].secret {
+				attributeValue = nullValue(ctx, attributeValue.Type(ctx))
+				if priorValue, ok := priorHandler.Attributes()[attributeName]; ok {
+					attributeValue = priorValue
+				}
+			}
+			attributes[attributeName] = attributeValue
+		}
+		handlers[name] = types.ObjectValueMust(handlerObject.AttributeTypes(ctx), attributes)
+	}
+	return types.ObjectValueMust(refreshed.AttributeTypes(ctx), handlers)
+}
+
+// attribute builders
+
+func stringConnectionAttribute(attribute schema.StringAttribute, field func(connection *client.DataSourceConnection) *string) connectionAttribute {
+	return connectionAttribute{
+		schema: attribute,
+		secret: attribute.Sensitive,
+		toAPI: func(value attr.Value, connection *client.DataSourceConnection) error {
+			*field(connection) = value.(types.String).ValueString()
+			return nil
+		},
+		fromAPI: func(connection client.DataSourceConnection) attr.Value {
+			return types.StringValue(*field(&connection))
+		},
+	}
+}
+
+func authenticationMethodAttribute(methods []string) connectionAttribute {
+	return connectionAttribute{
+		schema: schema.StringAttribute{
+			Optional:      true,
+			Computed:      true,
+			Description:   fmt.Sprintf("How Immuta authenticates, one of %s. Defaults to %s.", strings.Join(methods, ", "), methods[0]),
+			Validators:    []validator.String{stringOneOf(methods...)},
+			PlanModifiers: []planmodifier.String{defaultString(methods[0])},
+		},
+		toAPI: func(value attr.Value, connection *client.DataSourceConnection) error {
+			connection.AuthenticationMethod = authenticationMethods[value.(types.String).ValueString()].api
+			return nil
+		},
+		fromAPI: func(connection client.DataSourceConnection) attr.Value {
+			for _, method := range methods {
+				if strings.EqualFold(authenticationMethods[method].api, connection.AuthenticationMethod) {
+					return types.StringValue(method)
+				}
+			}
+			// Immuta leaves the default out
+			return types.StringValue(methods[0])
+		},
+	}
+}
+
+// hostAttributes are shared by the handlers connecting to a host
+func hostAttributes(defaultPort int64) map[string]connectionAttribute {
+	return map[string]connectionAttribute{
+		"hostname": stringConnectionAttribute(schema.StringAttribute{
+			Required:    true,
+			Description: "The hostname to connect to.",
+			Validators:  []validator.String{hostname()},
+		}, func(connection *client.DataSourceConnection) *string { return &connection.Hostname }),
+		"port": {
+			schema: schema.Int64Attribute{
+				Optional:      true,
+				Computed:      true,
+				Description:   fmt.Sprintf("The port to connect to. Defaults to %d.", defaultPort),
+				Validators:    []validator.Int64{int64Between{min: 1, max: 65535}},
+				PlanModifiers: []planmodifier.Int64{defaultInt64(defaultPort)},
+			},
+			toAPI: func(value attr.Value, connection *client.DataSourceConnection) error {
+				connection.Port = int(value.(types.Int64).ValueInt64())
+				return nil
+			},
+			fromAPI: func(connection client.DataSourceConnection) attr.Value {
+				if connection.Port == 0 {
+					return types.Int64Value(defaultPort)
+				}
+				return types.Int64Value(int64(connection.Port))
+			},
+		},
+		"database": stringConnectionAttribute(schema.StringAttribute{
+			Required:    true,
+			Description: "The database containing the tables.",
+			Validators:  []validator.String{stringNotEmpty()},
+		}, func(connection *client.DataSourceConnection) *string { return &connection.Database }),
+		"schema": stringConnectionAttribute(schema.StringAttribute{
+			Optional:    true,
+			Description: "The schema containing the tables, all schemas of the database are ingested when it is not set.",
+		}, func(connection *client.DataSourceConnection) *string { return &connection.Schema }),
+		"connection_string_options": stringConnectionAttribute(schema.StringAttribute{
+			Optional:    true,
+			Description: "Additional options appended to the connection string, e.g. \"role=ANALYST\".",
+		}, func(connection *client.DataSourceConnection) *string { return &connection.ConnectionStringOptions }),
+	}
+}
+
+func sslAttribute() map[string]connectionAttribute {
+	return map[string]connectionAttribute{
+		"ssl": {
+			schema: schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to connect over SSL.",
+			},
+			toAPI: func(value attr.Value, connection *client.DataSourceConnection) error {
+				connection.Ssl = value.(types.Bool).ValueBool()
+				return nil
+			},
+			fromAPI: func(connection client.DataSourceConnection) attr.Value {
+				return types.BoolValue(connection.Ssl)
+			},
+		},
+	}
+}
+
+// credentialAttributes are the attributes of every authentication method, handlers only get those of the methods
+// they support
+func credentialAttributes() map[string]connectionAttribute {
+	return map[string]connectionAttribute{
+		"username": stringConnectionAttribute(schema.StringAttribute{
+			Optional:    true,
+			Description: "The user to connect as, used by the password method.",
+		}, func(connection *client.DataSourceConnection) *string { return &connection.Username }),
+		"password": stringConnectionAttribute(schema.StringAttribute{
+			Optional:           true,
+			Sensitive:          true,
+			Description:        "The password of the user, used by the password method. It is stored in the state, prefer password_file to keep it out.",
+			DeprecationMessage: "The password is stored in the state in plain text, set password_file instead.",
+		}, func(connection *client.DataSourceConnection) *string { return &connection.Password }),
+		"password_file":    passwordFileAttribute(),
+		"password_version": passwordVersionAttribute(),
+		"password_hmac":    passwordHMACAttribute(),
+	}
+}
@@ -0,0 +1,195 @@
+package immuta
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"strings"
+)
+
+// Placeholders of name templates, Immuta replaces them with the names of each registered table
+const (
+	placeholderDatabase  = "DATABASE"
+	placeholderSchema    = "SCHEMA"
+	placeholderTableName = "TABLENAME"
+)
+
+var namePlaceholders = []string{placeholderDatabase, placeholderSchema, placeholderTableName}
+
+// the names the preview is rendered with when the configuration does not set them
+const (
+	sampleSchema = "SAMPLE_SCHEMA"
+	sampleTable  = "SAMPLE_TABLE"
+)
+
+// templatePlaceholders returns the placeholders of a name template as written, without the angle brackets
+func templatePlaceholders(template string) ([]string, error) {
+	var placeholders []string
+	rest := template
+	for {
+		start := strings.Index(rest, "<")
+		if start < 0 {
+			return placeholders, nil
+		}
+		end := strings.IndexAny(rest[start+1:], "<>")
+		if end < 0 || rest[start+1+end] == '<' {
+			return nil, fmt.Errorf("%q opens a placeholder with < without closing it with >", template)
+		}
+		placeholders = append(placeholders, rest[start+1:start+1+end])
+		rest = rest[start+1+end+1:]
+	}
+}
+
+// validPlaceholder accepts the supported placeholders in upper, lower or capitalized case
+func validPlaceholder(placeholder string) bool {
+	name := strings.ToUpper(placeholder)
+	for _, supported := range namePlaceholders {
+		if name == supported {
+			return placeholder == name || placeholder == strings.ToLower(name) || placeholder == capitalized(name)
+		}
+	}
+	return false
+}
+
+// renderNameTemplate replaces the placeholders of a valid template with the names, in the case of the placeholder
+func renderNameTemplate(template string, names map[string]string) string {
+	var rendered strings.Builder
+	rest := template
+	for {
+		start := strings.Index(rest, "<")
+		end := strings.Index(rest, ">")
+		if start < 0 || end < start {
+			break
+		}
+		placeholder := rest[start+1 : end]
+		name := names[strings.ToUpper(placeholder)]
+		switch placeholder {
+		case strings.ToUpper(placeholder):
+			name = strings.ToUpper(name)
+		case strings.ToLower(placeholder):
+			name = strings.ToLower(name)
+		default:
+			name = capitalized(name)
+		}
+		rendered.WriteString(rest[:start])
+		rendered.WriteString(name)
+		rest = rest[end+1:]
+	}
+	rendered.WriteString(rest)
+	return rendered.String()
+}
+
+func capitalized(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + strings.ToLower(value[1:])
+}
+
+func namePreviewAttributes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"data_source":    types.StringType,
+		"table":          types.StringType,
+		"schema":         types.StringType,
+		"schema_project": types.StringType,
+	}
+}
+
+// namePreviewAttribute is an object attribute rather than a nested one, the framework plans nested attributes from
+// the plan before the object's own plan modifiers ran
+func namePreviewAttribute() schema.ObjectAttribute {
+	return schema.ObjectAttribute{
+		Computed: true,
+		Description: "The names the name template gives a sample table, the first table of sources or " + sampleTable +
+			" in the database and schema of the connection: data_source, table, schema and schema_project.",
+		AttributeTypes: namePreviewAttributes(),
+		PlanModifiers:  []planmodifier.Object{namePreviewPlan{}},
+	}
+}
+
+// namePreviewPlan renders name_preview at plan time, so the names show up in the plan before Immuta is called
+type namePreviewPlan struct{}
+
+func (m namePreviewPlan) Description(_ context.Context) string {
+	return "The names rendered from the name template for a sample table."
+}
+
+func (m namePreviewPlan) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m namePreviewPlan) PlanModifyObject(ctx context.Context, req planmodifier.ObjectRequest, resp *planmodifier.ObjectResponse) {
+	var data DataSourceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.PlanValue = namePreview(ctx, data)
+}
+
+// namePreview renders the name template of the data source for the sample table
+func namePreview(ctx context.Context, data DataSourceResourceModel) types.Object {
+	if data.NameTemplate.IsNull() {
+		return types.ObjectNull(namePreviewAttributes())
+	}
+	if !isFullyKnown(ctx, data.NameTemplate, data.Connection, data.Sources) {
+		return types.ObjectUnknown(namePreviewAttributes())
+	}
+
+	nameTemplate := client.DataSourceNameTemplate{}
+	if diags := data.NameTemplate.As(ctx, &nameTemplate, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return types.ObjectNull(namePreviewAttributes())
+	}
+
+	database, schemaName := sampleLocation(data.Connection)
+	table := sampleTable
+	for _, element := range data.Sources.Elements() {
+		source := client.DataSourceSource{}
+		if diags := element.(types.Object).As(ctx, &source, defaultToZeroValue()); diags.HasError() || source.Table == "" {
+			continue
+		}
+		table = source.Table
+		if source.Schema != "" {
+			schemaName = source.Schema
+		}
+		break
+	}
+	if schemaName == "" {
+		schemaName = sampleSchema
+	}
+
+	names := map[string]string{placeholderDatabase: database, placeholderSchema: schemaName, placeholderTableName: table}
+	return types.ObjectValueMust(namePreviewAttributes(), map[string]attr.Value{
+		"data_source":    types.StringValue(renderNameTemplate(nameTemplate.DataSourceFormat, names)),
+		"table":          types.StringValue(renderNameTemplate(nameTemplate.TableFormat, names)),
+		"schema":         types.StringValue(renderNameTemplate(nameTemplate.SchemaFormat, names)),
+		"schema_project": types.StringValue(renderNameTemplate(nameTemplate.SchemaProjectNameFormat, names)),
+	})
+}
+
+// sampleLocation is the database and schema of the configured handler. Secrets are left out, they may be read
+// from files that only need to exist when the plan is applied.
+func sampleLocation(connection types.Object) (database, schemaName string) {
+	for _, name := range handlerNames() {
+		handlerObject, _ := connection.Attributes()[name].(types.Object)
+		if handlerObject.IsNull() || handlerObject.IsUnknown() {
+			continue
+		}
+
+		location := client.DataSourceConnection{}
+		for attributeName, attribute := range connectionHandlers[name].attributes {
+			value := handlerObject.Attributes()[attributeName]
+			if attribute.secret || value == nil || value.IsNull() || value.IsUnknown() {
+				continue
+			}
+			_ = attribute.toAPI(value, &location)
+		}
+		return location.Database, location.Schema
+	}
+	return "", ""
+}
@@ -0,0 +1,180 @@
+package immuta
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"os"
+	"strings"
+)
+
+// passwordFileAttribute sends the content of the file at the configured path as the password. Terraform stores
+// every configured value in the state, reading the password from a file is what keeps it out. password_hmac is what
+// shows a rewritten file as a change.
+func passwordFileAttribute() connectionAttribute {
+	return connectionAttribute{
+		schema: schema.StringAttribute{
+			Optional: true,
+			Description: "The path of a file holding the password of the user, used by the password method instead of password. " +
+				"The password is not stored in the state, only password_hmac, so changing the file sends the new password to Immuta.",
+			Validators: []validator.String{stringNotEmpty()},
+		},
+		secret: true,
+		toAPI: func(value attr.Value, connection *client.DataSourceConnection) error {
+			password, err := readSecretFile(value.(types.String).ValueString(), "password")
+			if err != nil {
+				return err
+			}
+			connection.Password = password
+			return nil
+		},
+		fromAPI: func(connection client.DataSourceConnection) attr.Value {
+			return types.StringValue("")
+		},
+	}
+}
+
+func passwordVersionAttribute() connectionAttribute {
+	return connectionAttribute{
+		schema: schema.Int64Attribute{
+			Optional: true,
+			Description: "Changing the version sends the password to Immuta again, e.g. after it was reset on the data platform " +
+				"while the password or its file stayed the same.",
+		},
+		secret: true,
+		toAPI: func(attr.Value, *client.DataSourceConnection) error {
+			return nil
+		},
+		fromAPI: func(connection client.DataSourceConnection) attr.Value {
+			return types.Int64Null()
+		},
+	}
+}
+
+// passwordSaltKey is the private state key of the salt password_hmac is keyed with
+const passwordSaltKey = "password_salt"
+
+func passwordHMACAttribute() connectionAttribute {
+	return connectionAttribute{
+		schema: schema.StringAttribute{
+			Computed: true,
+			Description: "The HMAC-SHA256 of the password from password or password_file, keyed with a random salt kept " +
+				"in the private state of the resource. A new password changes it and is sent to Immuta.",
+			PlanModifiers: []planmodifier.String{passwordHMAC{}},
+		},
+		secret: true,
+		toAPI: func(attr.Value, *client.DataSourceConnection) error {
+			return nil
+		},
+		fromAPI: func(connection client.DataSourceConnection) attr.Value {
+			return types.StringNull()
+		},
+	}
+}
+
+// passwordHMAC plans password_hmac from the password configured next to it. A bare hash of the password could be
+// guessed offline from the state, the salt is only in the private state, which Terraform does not show.
+type passwordHMAC struct{}
+
+func (m passwordHMAC) Description(_ context.Context) string {
+	return "The HMAC of the password configured in password or password_file."
+}
+
+func (m passwordHMAC) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m passwordHMAC) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	var handler types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath(), &handler)...)
+	if resp.Diagnostics.HasError() || handler.IsNull() || handler.IsUnknown() {
+		return
+	}
+
+	inline, _ := handler.Attributes()["password"].(types.String)
+	passwordPath, _ := handler.Attributes()["password_file"].(types.String)
+	if inline.IsUnknown() || passwordPath.IsUnknown() {
+		resp.PlanValue = types.StringUnknown()
+		return
+	}
+
+	var password string
+	switch {
+	case !inline.IsNull():
+		password = inline.ValueString()
+	case !passwordPath.IsNull():
+		var err error
+		password, err = readSecretFile(passwordPath.ValueString(), "password")
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path.ParentPath().AtName("password_file"), "Invalid connection attribute", err.Error())
+			return
+		}
+	default:
+		resp.PlanValue = types.StringNull()
+		return
+	}
+
+	salt, diags := passwordSalt(ctx, req, resp)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	resp.PlanValue = types.StringValue(passwordDigest(salt, password))
+}
+
+// passwordSalt returns the salt of the resource's private state, creating one for a resource that has none yet
+func passwordSalt(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) ([]byte, diag.Diagnostics) {
+	stored, diags := req.Private.GetKey(ctx, passwordSaltKey)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if stored != nil {
+		var salt []byte
+		if err := json.Unmarshal(stored, &salt); err == nil && len(salt) > 0 {
+			return salt, diags
+		}
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		diags.AddError("Could not create a salt", err.Error())
+		return nil, diags
+	}
+	encoded, err := json.Marshal(salt)
+	if err != nil {
+		diags.AddError("Could not create a salt", err.Error())
+		return nil, diags
+	}
+	diags.Append(resp.Private.SetKey(ctx, passwordSaltKey, encoded)...)
+	return salt, diags
+}
+
+func passwordDigest(salt []byte, password string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(password))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readSecretFile reads a password or passphrase file, leaving out the line break editors end files with
+func readSecretFile(secretPath, secret string) (string, error) {
+	content, err := os.ReadFile(secretPath)
+	if err != nil {
+		return "", fmt.Errorf("could not read the %s: %w", secret, err)
+	}
+	value := strings.TrimRight(string(content), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", secretPath)
+	}
+	return value, nil
+}
@@ -0,0 +1,34 @@
+package immuta
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func sourcesAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Optional: true,
+		Description: "The tables to register, every table of the connection's schema is registered when it is not set. " +
+			"The plan fails when Immuta rejects a table.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"schema": schema.StringAttribute{
+					Optional:    true,
+					Description: "The schema of the table, defaults to the schema of the connection.",
+				},
+				"table": schema.StringAttribute{
+					Required:    true,
+					Description: "The table to register.",
+				},
+			},
+		},
+	}
+}
+
+func (*DataSourceResourceModel) SourcesAttributes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"schema": types.StringType,
+		"table":  types.StringType,
+	}
+}
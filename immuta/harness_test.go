@@ -0,0 +1,293 @@
+package immuta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
+)
+
+// lifecycle drives a resource's CRUD methods against a fake Immuta, standing in for the Terraform CLI so
+// resources can be tested offline. M is the resource model.
+type lifecycle[M any] struct {
+	t        *testing.T
+	ctx      context.Context
+	server   *fakeimmuta.Server
+	resource resource.Resource
+	null     tfsdk.State
+}
+
+// newLifecycle starts a fake Immuta and configures the resource with a client for it
+func newLifecycle[M any](t *testing.T, r resource.Resource) *lifecycle[M] {
+	t.Helper()
+	ctx := context.Background()
+
+	server := fakeimmuta.NewServer()
+	t.Cleanup(server.Close)
+
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	l := &lifecycle[M]{t: t, ctx: ctx, server: server, resource: r}
+	l.check("schema", schemaResp.Diagnostics)
+	l.null = tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	l.configure(server.Client())
+	return l
+}
+
+// configure gives the resource another client, e.g. one recording its traffic
+func (l *lifecycle[M]) configure(immutaClient *client.ImmutaClient) {
+	l.t.Helper()
+	configureResp := resource.ConfigureResponse{}
+	l.resource.(resource.ResourceWithConfigure).Configure(l.ctx, resource.ConfigureRequest{ProviderData: immutaClient}, &configureResp)
+	l.check("configure", configureResp.Diagnostics)
+}
+
+func (l *lifecycle[M]) check(operation string, diags diag.Diagnostics) {
+	l.t.Helper()
+	if diags.HasError() {
+		l.t.Fatalf("%s failed: %v", operation, diags)
+	}
+}
+
+// empty returns a null state for the resource's schema
+func (l *lifecycle[M]) empty() tfsdk.State {
+	return tfsdk.State{Schema: l.null.Schema, Raw: l.null.Raw.Copy()}
+}
+
+func (l *lifecycle[M]) state(model *M) tfsdk.State {
+	l.t.Helper()
+	state := l.empty()
+	l.check("encoding the model", state.Set(l.ctx, model))
+	return state
+}
+
+func (l *lifecycle[M]) model(state tfsdk.State) *M {
+	l.t.Helper()
+	var model *M
+	l.check("decoding the state", state.Get(l.ctx, &model))
+	return model
+}
+
+func (l *lifecycle[M]) plan(model *M) tfsdk.Plan {
+	l.t.Helper()
+	state := l.state(model)
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
+
+// create applies a plan for a new resource and returns the resulting state
+func (l *lifecycle[M]) create(planned *M) *M {
+	l.t.Helper()
+	resp := resource.CreateResponse{State: l.empty()}
+	l.resource.Create(l.ctx, resource.CreateRequest{Plan: l.plan(planned)}, &resp)
+	l.check("create", resp.Diagnostics)
+	return l.model(resp.State)
+}
+
+// tryCreate applies a plan for a new resource, returning the diagnostics rather than failing the test
+func (l *lifecycle[M]) tryCreate(planned *M) diag.Diagnostics {
+	l.t.Helper()
+	resp := resource.CreateResponse{State: l.empty()}
+	l.resource.Create(l.ctx, resource.CreateRequest{Plan: l.plan(planned)}, &resp)
+	return resp.Diagnostics
+}
+
+// tryCreateState is tryCreate for operations that may fail after saving the state, the model is nil when no state
+// was saved
+func (l *lifecycle[M]) tryCreateState(planned *M) (*M, diag.Diagnostics) {
+	l.t.Helper()
+	resp := resource.CreateResponse{State: l.empty()}
+	l.resource.Create(l.ctx, resource.CreateRequest{Plan: l.plan(planned)}, &resp)
+	if resp.State.Raw.IsNull() {
+		return nil, resp.Diagnostics
+	}
+	return l.model(resp.State), resp.Diagnostics
+}
+
+// read refreshes the state, returning nil if the resource was removed from the state
+func (l *lifecycle[M]) read(prior *M) *M {
+	l.t.Helper()
+	refreshed, diags := l.tryRead(prior)
+	l.check("read", diags)
+	return refreshed
+}
+
+// tryRead refreshes the state like read, returning the diagnostics instead of failing the test
+func (l *lifecycle[M]) tryRead(prior *M) (*M, diag.Diagnostics) {
+	l.t.Helper()
+	state := l.state(prior)
+	resp := resource.ReadResponse{State: state}
+	l.resource.Read(l.ctx, resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() || resp.State.Raw.IsNull() {
+		return nil, resp.Diagnostics
+	}
+	return l.model(resp.State), resp.Diagnostics
+}
+
+// update applies a plan changing an existing resource and returns the resulting state
+func (l *lifecycle[M]) update(prior, planned *M) *M {
+	l.t.Helper()
+	plan := l.plan(planned)
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	l.resource.Update(l.ctx, resource.UpdateRequest{Plan: plan, State: l.state(prior)}, &resp)
+	l.check("update", resp.Diagnostics)
+	return l.model(resp.State)
+}
+
+// tryUpdate applies a plan changing an existing resource, returning the diagnostics rather than failing the test
+func (l *lifecycle[M]) tryUpdate(prior, planned *M) (*M, diag.Diagnostics) {
+	l.t.Helper()
+	plan := l.plan(planned)
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	l.resource.Update(l.ctx, resource.UpdateRequest{Plan: plan, State: l.state(prior)}, &resp)
+	return l.model(resp.State), resp.Diagnostics
+}
+
+func (l *lifecycle[M]) delete(prior *M) {
+	l.t.Helper()
+	state := l.state(prior)
+	resp := resource.DeleteResponse{State: state}
+	l.resource.Delete(l.ctx, resource.DeleteRequest{State: state}, &resp)
+	l.check("delete", resp.Diagnostics)
+}
+
+// modifyPlan runs the resource's plan modification and returns its diagnostics. prior is nil when the resource
+// is being created and planned is nil when it is being destroyed.
+func (l *lifecycle[M]) modifyPlan(prior, planned *M) diag.Diagnostics {
+	l.t.Helper()
+	state := l.empty()
+	if prior != nil {
+		state = l.state(prior)
+	}
+	plan := tfsdk.Plan{Schema: l.null.Schema, Raw: l.null.Raw.Copy()}
+	if planned != nil {
+		plan = l.plan(planned)
+	}
+
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	l.resource.(resource.ResourceWithModifyPlan).ModifyPlan(l.ctx, resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   plan,
+		State:  state,
+	}, &resp)
+	return resp.Diagnostics
+}
+
+// validate runs a configuration through an unconfigured provider server, as `terraform validate` does, so the
+// schema's validators run as well as the resource's own. Computed attributes of config must be null.
+func (l *lifecycle[M]) validate(config *M) []*tfprotov6.Diagnostic {
+	l.t.Helper()
+	server, typeName, value := l.protocol(config)
+	resp, err := server.ValidateResourceConfig(l.ctx, &tfprotov6.ValidateResourceConfigRequest{TypeName: typeName, Config: value})
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	return resp.Diagnostics
+}
+
+// validateErrors validates a configuration like validate and returns only its errors, leaving out warnings such as
+// those of deprecated attributes
+func (l *lifecycle[M]) validateErrors(config *M) []*tfprotov6.Diagnostic {
+	l.t.Helper()
+	var errors []*tfprotov6.Diagnostic
+	for _, d := range l.validate(config) {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			errors = append(errors, d)
+		}
+	}
+	return errors
+}
+
+// planCreate plans a new resource through an unconfigured provider server, so the schema's plan modifiers run,
+// and returns the planned model. Computed attributes of config must be null.
+func (l *lifecycle[M]) planCreate(config *M) *M {
+	l.t.Helper()
+	planned, _ := l.planChange(nil, nil, config)
+	return planned
+}
+
+// planChange plans config like planCreate, against the prior state and the private state Terraform kept for the
+// resource, and returns the planned model and private state. prior is nil when the resource is being created.
+func (l *lifecycle[M]) planChange(prior *M, priorPrivate []byte, config *M) (*M, []byte) {
+	l.t.Helper()
+	resp := l.planResourceChange(prior, priorPrivate, config)
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			l.t.Fatalf("plan failed: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	planned, err := resp.PlannedState.Unmarshal(l.null.Schema.Type().TerraformType(l.ctx))
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	return l.model(tfsdk.State{Schema: l.null.Schema, Raw: planned}), resp.PlannedPrivate
+}
+
+// tryPlanCreate plans a new resource like planCreate, returning the diagnostics rather than failing the test
+func (l *lifecycle[M]) tryPlanCreate(config *M) []*tfprotov6.Diagnostic {
+	l.t.Helper()
+	return l.planResourceChange(nil, nil, config).Diagnostics
+}
+
+func (l *lifecycle[M]) planResourceChange(prior *M, priorPrivate []byte, config *M) *tfprotov6.PlanResourceChangeResponse {
+	l.t.Helper()
+	server, typeName, value := l.protocol(config)
+	priorState := l.null.Raw
+	if prior != nil {
+		priorState = l.state(prior).Raw
+	}
+	priorValue, err := tfprotov6.NewDynamicValue(l.null.Schema.Type().TerraformType(l.ctx), priorState)
+	if err != nil {
+		l.t.Fatal(err)
+	}
+
+	resp, err := server.PlanResourceChange(l.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       &priorValue,
+		PriorPrivate:     priorPrivate,
+		ProposedNewState: value,
+		Config:           value,
+	})
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	return resp
+}
+
+func (l *lifecycle[M]) protocol(config *M) (tfprotov6.ProviderServer, string, *tfprotov6.DynamicValue) {
+	l.t.Helper()
+	server, err := providerserver.NewProtocol6WithError(New("test"))()
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	// Terraform always fetches the schemas first, the server learns the provider's type name from it
+	if _, err := server.GetProviderSchema(l.ctx, &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		l.t.Fatal(err)
+	}
+
+	metadata := resource.MetadataResponse{}
+	l.resource.Metadata(l.ctx, resource.MetadataRequest{ProviderTypeName: "immuta"}, &metadata)
+
+	value, err := tfprotov6.NewDynamicValue(l.null.Schema.Type().TerraformType(l.ctx), l.state(config).Raw)
+	if err != nil {
+		l.t.Fatal(err)
+	}
+	return server, metadata.TypeName, &value
+}
+
+// importState imports the resource by id and reads it, as `terraform import` does
+func (l *lifecycle[M]) importState(id string) *M {
+	l.t.Helper()
+	resp := resource.ImportStateResponse{State: l.empty()}
+	l.resource.(resource.ResourceWithImportState).ImportState(l.ctx, resource.ImportStateRequest{ID: id}, &resp)
+	l.check("import", resp.Diagnostics)
+	return l.read(l.model(resp.State))
+}
@@ -0,0 +1,23 @@
+package immuta
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Terraform operations on a resource
+const (
+	operationCreate = "create"
+	operationRead   = "read"
+	operationUpdate = "update"
+	operationDelete = "delete"
+	operationImport = "import"
+	operationPlan   = "plan"
+)
+
+// withResourceLogFields attaches the resource type and Terraform operation to everything logged while handling it,
+// including the client's request logs, so the logs can be filtered down to a single failing resource
+func withResourceLogFields(ctx context.Context, resourceType, operation string) context.Context {
+	ctx = tflog.SetField(ctx, "resource", resourceType)
+	return tflog.SetField(ctx, "operation", operation)
+}
@@ -2,14 +2,21 @@ package immuta
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	frameworkschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/immuta/terraform-provider-immuta/client"
 )
 
@@ -21,6 +28,8 @@ type Provider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+	// clientOptions are added to the options the client is created with, tests use them to record or replay traffic
+	clientOptions []client.Option
 }
 
 func New(version string) provider.Provider {
@@ -41,8 +50,40 @@ func (p Provider) Metadata(_ context.Context, _ provider.MetadataRequest, respon
 }
 
 type ProviderModel struct {
-	ApiToken types.String `tfsdk:"api_token"`
-	Host     types.String `tfsdk:"host"`
+	ApiToken     types.String `tfsdk:"api_token"`
+	Host         types.String `tfsdk:"host"`
+	ApiKey       types.String `tfsdk:"api_key"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+	IamId        types.String `tfsdk:"iam_id"`
+	TokenFile    types.String `tfsdk:"token_file"`
+	TokenCommand types.List   `tfsdk:"token_command"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMinWait types.Int64  `tfsdk:"retry_min_wait"`
+	RetryMaxWait types.Int64  `tfsdk:"retry_max_wait"`
+
+	RequestTimeout types.Int64 `tfsdk:"request_timeout"`
+
+	MaxIdleConnections    types.Int64 `tfsdk:"max_idle_connections"`
+	MaxConnectionsPerHost types.Int64 `tfsdk:"max_connections_per_host"`
+	IdleConnectionTimeout types.Int64 `tfsdk:"idle_connection_timeout"`
+	DisableHTTP2          types.Bool  `tfsdk:"disable_http2"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	Scheme             types.String `tfsdk:"scheme"`
+	BasePath           types.String `tfsdk:"base_path"`
+
+	SkipCredentialsValidation types.Bool `tfsdk:"skip_credentials_validation"`
+
+	RedactLogFields types.List `tfsdk:"redact_log_fields"`
 }
 
 func (p Provider) Schema(_ context.Context, _ provider.SchemaRequest, response *provider.SchemaResponse) {
@@ -56,6 +97,115 @@ func (p Provider) Schema(_ context.Context, _ provider.SchemaRequest, response *
 				Description: "The endpoint to use. Can be set with IMMUTA_HOST.",
 				Optional:    true,
 			},
+			"api_key": frameworkschema.StringAttribute{
+				Description: "An API key exchanged for a session token, which is refreshed when it expires. Can be set with IMMUTA_API_KEY.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"username": frameworkschema.StringAttribute{
+				Description: "Username to log in with for a session token, which is refreshed when it expires. Requires password. Can be set with IMMUTA_USERNAME.",
+				Optional:    true,
+			},
+			"password": frameworkschema.StringAttribute{
+				Description: "Password to log in with. Can be set with IMMUTA_PASSWORD.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"iam_id": frameworkschema.StringAttribute{
+				Description: fmt.Sprintf("Identity manager the username belongs to. Defaults to %q.", client.DefaultIamId),
+				Optional:    true,
+			},
+			"token_file": frameworkschema.StringAttribute{
+				Description: "Path to a file holding the token, read again when the token is rejected. Can be set with IMMUTA_TOKEN_FILE.",
+				Optional:    true,
+			},
+			"token_command": frameworkschema.ListAttribute{
+				Description: "Command and arguments of an external credential helper printing the token, run again when the token is rejected.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"max_retries": frameworkschema.Int64Attribute{
+				Description: fmt.Sprintf("How many times a request is retried after a transient failure (429, 502, 503, 504 or a reset connection), 0 disables retrying. Defaults to %d.", client.DefaultMaxRetries),
+				Optional:    true,
+			},
+			"retry_min_wait": frameworkschema.Int64Attribute{
+				Description: fmt.Sprintf("Minimum number of seconds to wait before retrying a request. Defaults to %d.", int(client.DefaultMinBackoff.Seconds())),
+				Optional:    true,
+			},
+			"retry_max_wait": frameworkschema.Int64Attribute{
+				Description: fmt.Sprintf("Maximum number of seconds to wait before retrying a request, including any Retry-After sent by Immuta. Defaults to %d.", int(client.DefaultMaxBackoff.Seconds())),
+				Optional:    true,
+			},
+			"request_timeout": frameworkschema.Int64Attribute{
+				Description: fmt.Sprintf("Maximum number of seconds to wait for a single request to Immuta, 0 disables the limit. Operations of resources with a timeouts block are also bounded by those timeouts. Defaults to %d.", int(client.DefaultRequestTimeout.Seconds())),
+				Optional:    true,
+			},
+			"max_idle_connections": frameworkschema.Int64Attribute{
+				Description: fmt.Sprintf("Maximum number of idle connections kept open to Immuta for reuse. Defaults to %d.", client.DefaultMaxIdleConns),
+				Optional:    true,
+			},
+			"max_connections_per_host": frameworkschema.Int64Attribute{
+				Description: "Maximum number of open connections to Immuta, 0 means no limit. Defaults to 0.",
+				Optional:    true,
+			},
+			"idle_connection_timeout": frameworkschema.Int64Attribute{
+				Description: fmt.Sprintf("Number of seconds an idle connection is kept open before being closed. Defaults to %d.", int(client.DefaultIdleConnTimeout.Seconds())),
+				Optional:    true,
+			},
+			"disable_http2": frameworkschema.BoolAttribute{
+				Description: "Only use HTTP/1.1 when talking to Immuta. Defaults to false.",
+				Optional:    true,
+			},
+			"requests_per_second": frameworkschema.Float64Attribute{
+				Description: "Maximum number of requests per second sent to Immuta, shared by every resource. 0 means no limit. Defaults to 0.",
+				Optional:    true,
+			},
+			"max_concurrent_requests": frameworkschema.Int64Attribute{
+				Description: "Maximum number of requests in flight to Immuta at once, shared by every resource. 0 means no limit. Defaults to 0.",
+				Optional:    true,
+			},
+			"ca_cert_pem": frameworkschema.StringAttribute{
+				Description: "PEM encoded CA certificates to trust in addition to the system pool, e.g. for a self-hosted Immuta with an internal CA. Conflicts with ca_cert_file.",
+				Optional:    true,
+			},
+			"ca_cert_file": frameworkschema.StringAttribute{
+				Description: "Path to a file of PEM encoded CA certificates to trust in addition to the system pool. Can be set with IMMUTA_CA_CERT_FILE. Conflicts with ca_cert_pem.",
+				Optional:    true,
+			},
+			"client_cert": frameworkschema.StringAttribute{
+				Description: "PEM encoded client certificate for mutual TLS. Requires client_key.",
+				Optional:    true,
+			},
+			"client_key": frameworkschema.StringAttribute{
+				Description: "PEM encoded private key of the client certificate for mutual TLS. Requires client_cert.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"insecure_skip_verify": frameworkschema.BoolAttribute{
+				Description: "Do not verify the certificate presented by Immuta. Only use this for testing. Defaults to false.",
+				Optional:    true,
+			},
+			"proxy_url": frameworkschema.StringAttribute{
+				Description: "URL of the proxy to send requests through. Defaults to the proxy from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.",
+				Optional:    true,
+			},
+			"scheme": frameworkschema.StringAttribute{
+				Description: fmt.Sprintf("Scheme used to reach Immuta, %q or %q. Defaults to %q.", client.SchemeHTTPS, client.SchemeHTTP, client.SchemeHTTPS),
+				Optional:    true,
+			},
+			"base_path": frameworkschema.StringAttribute{
+				Description: "Path prefixed to every request, for an Immuta served below a path on a shared host.",
+				Optional:    true,
+			},
+			"skip_credentials_validation": frameworkschema.BoolAttribute{
+				Description: "Do not check the host and credentials when the provider is configured, nor detect the Immuta release, e.g. for offline plans. Can be set with IMMUTA_SKIP_CREDENTIALS_VALIDATION. Defaults to false.",
+				Optional:    true,
+			},
+			"redact_log_fields": frameworkschema.ListAttribute{
+				Description: fmt.Sprintf("JSON fields masked in request and response bodies logged with TF_LOG=DEBUG, on top of %s. Tokens and cookies are always masked.", strings.Join(client.DefaultRedactedFields, ", ")),
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -65,35 +215,282 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 
 	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
 
-	apiToken := os.Getenv("IMMUTA_API_TOKEN")
 	host := os.Getenv("IMMUTA_HOST")
 
-	if config.ApiToken.ValueString() != "" {
-		apiToken = config.ApiToken.ValueString()
-	}
-
 	if config.Host.ValueString() != "" {
 		host = config.Host.ValueString()
 	}
 
-	if apiToken == "" {
-		response.Diagnostics.AddError("api_token is required", "api_token is required")
-	}
+	authOption, diags := providerAuth(ctx, config)
+	response.Diagnostics.Append(diags...)
 
 	if host == "" {
 		response.Diagnostics.AddError("host is required", "host is required")
 	}
 
+	retryConfig := client.DefaultRetryConfig()
+	if !config.MaxRetries.IsNull() {
+		retryConfig.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+	if !config.RetryMinWait.IsNull() {
+		retryConfig.MinBackoff = time.Duration(config.RetryMinWait.ValueInt64()) * time.Second
+	}
+	if !config.RetryMaxWait.IsNull() {
+		retryConfig.MaxBackoff = time.Duration(config.RetryMaxWait.ValueInt64()) * time.Second
+	}
+
+	if retryConfig.MaxRetries < 0 {
+		response.Diagnostics.AddError("max_retries must not be negative", "max_retries must be 0 or greater")
+	}
+
+	if retryConfig.MinBackoff > retryConfig.MaxBackoff {
+		response.Diagnostics.AddError("retry_min_wait is greater than retry_max_wait", "retry_min_wait must be less than or equal to retry_max_wait")
+	}
+
+	requestTimeout := client.DefaultRequestTimeout
+	if !config.RequestTimeout.IsNull() {
+		requestTimeout = time.Duration(config.RequestTimeout.ValueInt64()) * time.Second
+	}
+
+	if requestTimeout < 0 {
+		response.Diagnostics.AddError("request_timeout must not be negative", "request_timeout must be 0 or greater")
+	}
+
+	poolConfig := client.DefaultPoolConfig()
+	if !config.MaxIdleConnections.IsNull() {
+		poolConfig.MaxIdleConns = int(config.MaxIdleConnections.ValueInt64())
+	}
+	if !config.MaxConnectionsPerHost.IsNull() {
+		poolConfig.MaxConnsPerHost = int(config.MaxConnectionsPerHost.ValueInt64())
+	}
+	if !config.IdleConnectionTimeout.IsNull() {
+		poolConfig.IdleConnTimeout = time.Duration(config.IdleConnectionTimeout.ValueInt64()) * time.Second
+	}
+	poolConfig.DisableHTTP2 = config.DisableHTTP2.ValueBool()
+
+	if poolConfig.MaxIdleConns < 0 || poolConfig.MaxConnsPerHost < 0 || poolConfig.IdleConnTimeout < 0 {
+		response.Diagnostics.AddError("connection pool settings must not be negative", "max_idle_connections, max_connections_per_host and idle_connection_timeout must be 0 or greater")
+	}
+
+	rateLimitConfig := client.DefaultRateLimitConfig()
+	if !config.RequestsPerSecond.IsNull() {
+		rateLimitConfig.RequestsPerSecond = config.RequestsPerSecond.ValueFloat64()
+	}
+	if !config.MaxConcurrentRequests.IsNull() {
+		rateLimitConfig.MaxConcurrent = int(config.MaxConcurrentRequests.ValueInt64())
+	}
+
+	if rateLimitConfig.RequestsPerSecond < 0 || rateLimitConfig.MaxConcurrent < 0 {
+		response.Diagnostics.AddError("rate limits must not be negative", "requests_per_second and max_concurrent_requests must be 0 or greater")
+	}
+
+	tlsConfig, diags := providerTLSConfig(config)
+	response.Diagnostics.Append(diags...)
+
+	var proxyURL *url.URL
+	if config.ProxyURL.ValueString() != "" {
+		var err error
+		proxyURL, err = url.Parse(config.ProxyURL.ValueString())
+		if err != nil || proxyURL.Host == "" {
+			response.Diagnostics.AddError("proxy_url is invalid", fmt.Sprintf("proxy_url must be an absolute URL such as http://proxy:3128, got %q", config.ProxyURL.ValueString()))
+		}
+	}
+
+	scheme := client.SchemeHTTPS
+	if config.Scheme.ValueString() != "" {
+		scheme = config.Scheme.ValueString()
+	}
+
+	if err := client.ValidateScheme(scheme); err != nil {
+		response.Diagnostics.AddError("scheme is invalid", err.Error())
+	}
+
+	redactLogFields, diags := goListFromTf[string](ctx, config.RedactLogFields)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	opts := []client.Option{
+		authOption,
+		client.WithRetryConfig(retryConfig),
+		client.WithRequestTimeout(requestTimeout),
+		client.WithPoolConfig(poolConfig),
+		client.WithRateLimitConfig(rateLimitConfig),
+		client.WithTLSConfig(tlsConfig),
+		client.WithScheme(scheme),
+		client.WithBasePath(config.BasePath.ValueString()),
+		client.WithRedactedFields(redactLogFields...),
+	}
+
+	if proxyURL != nil {
+		opts = append(opts, client.WithProxyURL(proxyURL))
+	}
+
+	opts = append(opts, p.clientOptions...)
+
 	userAgent := fmt.Sprintf("HashiCorp Terraform/%s (+https://www.terraform.io) Terraform Plugin SDK/%s", "immuta", "immuta")
 
-	immutaClient := client.NewClient(host, apiToken, userAgent)
+	immutaClient := client.NewClient(host, "", userAgent, opts...)
+
+	skipValidation := os.Getenv("IMMUTA_SKIP_CREDENTIALS_VALIDATION") == "true"
+	if !config.SkipCredentialsValidation.IsNull() {
+		skipValidation = config.SkipCredentialsValidation.ValueBool()
+	}
 
-	// todo validate client once low cost API call is available
+	if !skipValidation {
+		if _, err := immutaClient.Validate(ctx); err != nil {
+			response.Diagnostics.Append(connectionErrorDiagnostic(err))
+			return
+		}
+
+		// tenants are not required to report their release, without it nothing is checked
+		if err := immutaClient.DetectRelease(ctx); err != nil {
+			tflog.Debug(ctx, "Could not detect the Immuta release", map[string]interface{}{"error": err.Error()})
+		}
+	}
 
 	response.DataSourceData = immutaClient
 	response.ResourceData = immutaClient
 }
 
+// connectionErrorDiagnostic reports a failed credentials check at the provider level
+func connectionErrorDiagnostic(err error) diag.Diagnostic {
+	var connectionErr *client.ConnectionError
+	if !errors.As(err, &connectionErr) {
+		return diag.NewErrorDiagnostic("Could not validate the Immuta credentials", err.Error())
+	}
+	return diag.NewErrorDiagnostic(
+		connectionErr.Summary,
+		fmt.Sprintf("%s\n\n%s", connectionErr.Err, connectionErr.Hint),
+	)
+}
+
+// providerAuth picks the single authentication mode configured for the provider, falling back to the environment
+func providerAuth(ctx context.Context, config ProviderModel) (client.Option, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+
+	tokenCommand, tokenCommandDiags := goListFromTf[string](ctx, config.TokenCommand)
+	diags.Append(tokenCommandDiags...)
+
+	creds := providerCredentials{
+		apiToken:     config.ApiToken.ValueString(),
+		apiKey:       config.ApiKey.ValueString(),
+		username:     config.Username.ValueString(),
+		password:     config.Password.ValueString(),
+		tokenFile:    config.TokenFile.ValueString(),
+		tokenCommand: tokenCommand,
+	}
+
+	if creds.modes() == nil {
+		creds = providerCredentials{
+			apiToken:  os.Getenv("IMMUTA_API_TOKEN"),
+			apiKey:    os.Getenv("IMMUTA_API_KEY"),
+			username:  os.Getenv("IMMUTA_USERNAME"),
+			password:  os.Getenv("IMMUTA_PASSWORD"),
+			tokenFile: os.Getenv("IMMUTA_TOKEN_FILE"),
+		}
+	}
+
+	modes := creds.modes()
+	switch {
+	case len(modes) == 0:
+		diags.AddError("credentials are required", "one of api_token, api_key, username and password, token_file or token_command is required")
+		return nil, diags
+	case len(modes) > 1:
+		diags.AddError("only one authentication method can be used", fmt.Sprintf("found %s, only one of them can be set", strings.Join(modes, ", ")))
+		return nil, diags
+	}
+
+	switch {
+	case creds.apiToken != "":
+		return client.WithTokenSource(client.StaticToken(creds.apiToken)), diags
+	case creds.apiKey != "":
+		return client.WithAPIKey(creds.apiKey), diags
+	case creds.tokenFile != "":
+		return client.WithTokenSource(client.FileToken(creds.tokenFile)), diags
+	case len(creds.tokenCommand) > 0:
+		return client.WithTokenSource(client.CommandToken(creds.tokenCommand)), diags
+	}
+
+	if creds.username == "" || creds.password == "" {
+		diags.AddError("username and password are required together", "both username and password must be set to log in")
+		return nil, diags
+	}
+
+	return client.WithPasswordLogin(creds.username, creds.password, config.IamId.ValueString()), diags
+}
+
+type providerCredentials struct {
+	apiToken     string
+	apiKey       string
+	username     string
+	password     string
+	tokenFile    string
+	tokenCommand []string
+}
+
+// modes lists the authentication modes that have been set
+func (c providerCredentials) modes() []string {
+	var modes []string
+	if c.apiToken != "" {
+		modes = append(modes, "api_token")
+	}
+	if c.apiKey != "" {
+		modes = append(modes, "api_key")
+	}
+	if c.username != "" || c.password != "" {
+		modes = append(modes, "username and password")
+	}
+	if c.tokenFile != "" {
+		modes = append(modes, "token_file")
+	}
+	if len(c.tokenCommand) > 0 {
+		modes = append(modes, "token_command")
+	}
+	return modes
+}
+
+// providerTLSConfig reads the CA and client certificates configured for the provider
+func providerTLSConfig(config ProviderModel) (*tls.Config, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+
+	caCertFile := os.Getenv("IMMUTA_CA_CERT_FILE")
+	if config.CACertFile.ValueString() != "" {
+		caCertFile = config.CACertFile.ValueString()
+	}
+
+	if config.CACertPEM.ValueString() != "" && caCertFile != "" {
+		diags.AddError("ca_cert_pem conflicts with ca_cert_file", "only one of ca_cert_pem and ca_cert_file can be set")
+		return nil, diags
+	}
+
+	options := client.TLSOptions{
+		CACertPEM:          []byte(config.CACertPEM.ValueString()),
+		ClientCertPEM:      []byte(config.ClientCert.ValueString()),
+		ClientKeyPEM:       []byte(config.ClientKey.ValueString()),
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+	}
+
+	if caCertFile != "" {
+		caCertPEM, err := os.ReadFile(caCertFile)
+		if err != nil {
+			diags.AddError("Could not read ca_cert_file", err.Error())
+			return nil, diags
+		}
+		options.CACertPEM = caCertPEM
+	}
+
+	tlsConfig, err := client.NewTLSConfig(options)
+	if err != nil {
+		diags.AddError("Invalid TLS configuration", err.Error())
+		return nil, diags
+	}
+
+	return tlsConfig, diags
+}
+
 func (p Provider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{}
 }
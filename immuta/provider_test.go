@@ -1,9 +1,12 @@
 package immuta
 
 import (
+	"errors"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/immuta/terraform-provider-immuta/client"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -16,7 +19,53 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"immuta": providerserver.NewProtocol6WithError(New("test")),
 }
 
+// IMMUTA_CASSETTE_MODE=record saves the traffic of acceptance tests using testAccCassetteProviderFactories
+// to testdata/cassettes, IMMUTA_CASSETTE_MODE=replay runs them from the saved traffic without a tenant. No
+// cassettes are committed, so nothing runs offline yet: replaying skips the tests whose cassette has not been recorded.
+const testAccCassetteModeEnv = "IMMUTA_CASSETTE_MODE"
+
+// testAccCassetteProviderFactories instantiates a provider which records or replays the test's requests,
+// depending on IMMUTA_CASSETTE_MODE. It is the same as testAccProtoV6ProviderFactories when the mode is not set.
+func testAccCassetteProviderFactories(t *testing.T) map[string]func() (tfprotov6.ProviderServer, error) {
+	mode := client.CassetteMode(os.Getenv(testAccCassetteModeEnv))
+	if mode == "" {
+		return testAccProtoV6ProviderFactories
+	}
+
+	cassette, err := client.NewCassette(filepath.Join("testdata", "cassettes", t.Name()+".json"), mode)
+	if errors.Is(err, client.ErrCassetteMissing) {
+		t.Skipf("%s, record it with %s=%s", err, testAccCassetteModeEnv, client.CassetteRecord)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := cassette.Save(); err != nil {
+			t.Errorf("could not save cassette: %s", err)
+		}
+	})
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"immuta": providerserver.NewProtocol6WithError(&Provider{
+			version:       "test",
+			clientOptions: []client.Option{client.WithCassette(cassette)},
+		}),
+	}
+}
+
+// testAccReplaying reports whether acceptance tests are replaying cassettes instead of reaching a tenant
+func testAccReplaying() bool {
+	return os.Getenv(testAccCassetteModeEnv) == string(client.CassetteReplay)
+}
+
 func testAccPreCheck(t *testing.T) {
+	if testAccReplaying() {
+		// the provider still needs a host and credentials, but they are never used
+		t.Setenv("IMMUTA_HOST", "dev-replay.immuta.invalid")
+		t.Setenv("IMMUTA_API_TOKEN", "replay")
+		return
+	}
+
 	if os.Getenv("IMMUTA_API_TOKEN") == "" {
 		t.Fatal("Immuta API token must be set for acceptance tests")
 	}
@@ -21,7 +21,7 @@ func NewBimAttributeResource() resource.Resource {
 
 // BimAttributeResource defines the resource implementation.
 type BimAttributeResource struct {
-	client *client.ImmutaClient
+	bimAttributes client.BimAttributesService
 }
 
 // BimAttributeResourceModel describes the resource data model.
@@ -76,21 +76,24 @@ func (r *BimAttributeResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	immutaClient, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *immutaClient.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = immutaClient
+	r.bimAttributes = immutaClient.BimAttributes()
 }
 
 func (r *BimAttributeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_attribute", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimAttributeResourceModel
 
 	// Read Terraform plan data into the model
@@ -100,7 +103,7 @@ func (r *BimAttributeResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	err := r.CreateBimAttribute(ctx, data)
+	err := r.bimAttributes.Add(ctx, bimAttributeFromResourceData(data))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating BimAttribute",
@@ -116,6 +119,9 @@ func (r *BimAttributeResource) Create(ctx context.Context, req resource.CreateRe
 }
 
 func (r *BimAttributeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_attribute", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimAttributeResourceModel
 
 	// Read Terraform prior state data into the model
@@ -125,7 +131,12 @@ func (r *BimAttributeResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	userAttributes, err := r.GetBimAuthorizations(ctx, data)
+	userAttributes, err := r.bimAttributes.Get(ctx, data.IamId.ValueString(), data.ModelType.ValueString(), data.ModelId.ValueString())
+	if client.IsNotFound(err) {
+		// The user or group no longer exists, so neither does the attribute
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading BimAttribute",
@@ -156,6 +167,9 @@ func (r *BimAttributeResource) Read(ctx context.Context, req resource.ReadReques
 }
 
 func (r *BimAttributeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_attribute", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimAttributeResourceModel
 
 	// Read Terraform plan data into the model
@@ -173,6 +187,9 @@ func (r *BimAttributeResource) Update(ctx context.Context, req resource.UpdateRe
 }
 
 func (r *BimAttributeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_attribute", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimAttributeResourceModel
 
 	// Read Terraform prior state data into the model
@@ -182,7 +199,7 @@ func (r *BimAttributeResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	err := r.DeleteBimAuthorization(ctx, data)
+	err := r.bimAttributes.Remove(ctx, bimAttributeFromResourceData(data))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting BimAttribute",
@@ -193,6 +210,9 @@ func (r *BimAttributeResource) Delete(ctx context.Context, req resource.DeleteRe
 }
 
 func (r *BimAttributeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_attribute", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
+
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
@@ -201,25 +221,12 @@ func createBimAttributeTerraformId(data *BimAttributeResourceModel) string {
 	return fmt.Sprintf("%s/%s/%s/%s/%s", data.IamId, data.ModelType, data.ModelId, data.Key, data.Value)
 }
 
-// CRUD methods
-
-func (r *BimAttributeResource) CreateBimAttribute(ctx context.Context, data *BimAttributeResourceModel) (err error) {
-	err = r.client.Put(fmt.Sprintf("/bim/iam/%s/%s/%s/authorizations/%s/%s", data.IamId.ValueString(), data.ModelType.ValueString(), data.ModelId.ValueString(), data.Key.ValueString(), data.Value.ValueString()), "", nil, nil)
-	return
-}
-
-func (r *BimAttributeResource) GetBimAuthorizations(ctx context.Context, data *BimAttributeResourceModel) (resp *BimAttributeUserResponse, err error) {
-	err = r.client.Get(fmt.Sprintf("/bim/iam/%s/%s/%s", data.IamId.ValueString(), data.ModelType.ValueString(), data.ModelId.ValueString()), "", map[string]string{}, &resp)
-	return
-}
-
-func (r *BimAttributeResource) DeleteBimAuthorization(ctx context.Context, data *BimAttributeResourceModel) (err error) {
-	err = r.client.Delete(fmt.Sprintf("/bim/iam/%s/%s/%s/authorizations/%s/%s", data.IamId.ValueString(), data.ModelType.ValueString(), data.ModelId.ValueString(), data.Key.ValueString(), data.Value.ValueString()), "", nil, nil)
-	return
-}
-
-// Domain-specific methods
-
-type BimAttributeUserResponse struct {
-	BimAuthorizations map[string][]string `json:"bimAuthorizations"`
+func bimAttributeFromResourceData(data *BimAttributeResourceModel) client.BimAttribute {
+	return client.BimAttribute{
+		IamId:     data.IamId.ValueString(),
+		ModelType: data.ModelType.ValueString(),
+		ModelId:   data.ModelId.ValueString(),
+		Key:       data.Key.ValueString(),
+		Value:     data.Value.ValueString(),
+	}
 }
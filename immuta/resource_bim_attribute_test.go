@@ -1,8 +1,11 @@
 package immuta
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
 	"testing"
 )
 
@@ -56,3 +59,46 @@ func testAccBimAttributeConfigBasic(key string, value string) string {
 	}
 `
 }
+
+func TestBimAttribute_lifecycle(t *testing.T) {
+	l := newLifecycle[BimAttributeResourceModel](t, NewBimAttributeResource())
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.Users[testAccBimAttributeUser] = &client.BimUser{Userid: testAccBimAttributeUser, Iamid: client.DefaultIamId}
+	})
+	attributesKey := fakeimmuta.AuthorizationsKey(client.DefaultIamId, "user", testAccBimAttributeUser)
+
+	// Immuta lower cases attribute names, read has to find the attribute regardless
+	planned := &BimAttributeResourceModel{
+		Id:        types.StringUnknown(),
+		IamId:     types.StringValue(client.DefaultIamId),
+		ModelType: types.StringValue("user"),
+		ModelId:   types.StringValue(testAccBimAttributeUser),
+		Key:       types.StringValue("TF_Acc_Test_Key"),
+		Value:     types.StringValue("tf_acc_test_value"),
+	}
+	created := l.create(planned)
+	if l.read(created) == nil {
+		t.Fatal("expected the attribute to be found after create")
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		delete(state.Authorizations[attributesKey], "tf_acc_test_key")
+	})
+	if l.read(created) != nil {
+		t.Error("expected an attribute removed outside Terraform to be removed from the state")
+	}
+
+	created = l.create(planned)
+	l.delete(created)
+	if l.read(created) != nil {
+		t.Error("expected a deleted attribute to be removed from the state")
+	}
+
+	created = l.create(planned)
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		delete(state.Users, testAccBimAttributeUser)
+	})
+	if l.read(created) != nil {
+		t.Error("expected the attribute of a deleted user to be removed from the state")
+	}
+}
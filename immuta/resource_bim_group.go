@@ -3,7 +3,6 @@ package immuta
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -22,7 +21,7 @@ func NewBimGroupResource() resource.Resource {
 
 // BimGroupResource defines the resource implementation.
 type BimGroupResource struct {
-	client *client.ImmutaClient
+	bimGroups client.BimGroupsService
 }
 
 // BimGroupResourceModel describes the resource data model.
@@ -77,21 +76,24 @@ func (r *BimGroupResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.bimGroups = immutaClient.BimGroups()
 }
 
 func (r *BimGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupResourceModel
 
 	// Read Terraform plan data into the model
@@ -101,14 +103,14 @@ func (r *BimGroupResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	bimGroupInput := BimGroupInput{
+	bimGroupInput := client.BimGroupInput{
 		IamId:       data.IamId.ValueString(),
 		Name:        data.Name.ValueString(),
 		Email:       data.Email.ValueString(),
 		Description: data.Description.ValueString(),
 	}
 
-	bimGroupResponse, err := r.CreateBimGroup(bimGroupInput)
+	bimGroupResponse, err := r.bimGroups.Create(ctx, bimGroupInput)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating BimGroup",
@@ -123,6 +125,9 @@ func (r *BimGroupResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *BimGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupResourceModel
 
 	// Read Terraform prior state data into the model
@@ -132,7 +137,12 @@ func (r *BimGroupResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	bimGroupResponse, err := r.GetBimGroup(data.Id.String())
+	bimGroupResponse, err := r.bimGroups.Get(ctx, data.Id.String())
+	if client.IsNotFound(err) {
+		// Group no longer exists, remove from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading BimGroup",
@@ -179,6 +189,9 @@ func (r *BimGroupResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 // Update group details
 func (r *BimGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupResourceModel
 
 	// Read Terraform plan data into the model
@@ -188,12 +201,12 @@ func (r *BimGroupResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	bimGroupProfile := BimGroupProfile{}
+	bimGroupProfile := client.BimGroupProfile{}
 	bimGroupProfile.Name = data.Name.ValueString()
 	bimGroupProfile.Email = data.Email.ValueString()
 	bimGroupProfile.Description = data.Description.ValueString()
 
-	bimGroupResponse, err := r.UpdateBimGroup(data.Id.String(), &bimGroupProfile)
+	bimGroupResponse, err := r.bimGroups.Update(ctx, data.Id.String(), &bimGroupProfile)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating BimGroup",
@@ -215,6 +228,9 @@ func (r *BimGroupResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 func (r *BimGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupResourceModel
 
 	// Read Terraform prior state data into the model
@@ -224,7 +240,7 @@ func (r *BimGroupResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	err := r.DeleteBimGroup(data.Id.String())
+	err := r.bimGroups.Delete(ctx, data.Id.String())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting BimGroup",
@@ -235,50 +251,8 @@ func (r *BimGroupResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (r *BimGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
-
-// CRUD methods
+	ctx, span := startOperation(ctx, "immuta_bim_group", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
 
-func (r *BimGroupResource) GetBimGroup(groupId string) (bimGroupResponse *BimGroup, err error) {
-	err = r.client.Get(fmt.Sprintf("/bim/group/%s", groupId), "", nil, &bimGroupResponse)
-	return
-}
-
-func (r *BimGroupResource) CreateBimGroup(bimGroup BimGroupInput) (bimGroupResponse *BimGroup, err error) {
-	err = r.client.Post("/bim/group", "", bimGroup, &bimGroupResponse)
-	return
-}
-
-func (r *BimGroupResource) DeleteBimGroup(groupId string) (err error) {
-	err = r.client.Delete("/bim/group/"+groupId, "", nil, nil)
-	return
-}
-
-func (r *BimGroupResource) UpdateBimGroup(groupId string, bimGroupProfile *BimGroupProfile) (bimGroupResponse *BimGroup, err error) {
-	err = r.client.Put("/bim/group/"+groupId, "", bimGroupProfile, &bimGroupResponse)
-	return
-}
-
-// Domain specific types
-
-type BimGroupProfile struct {
-	Name        string `json:"name"`
-	Email       string `json:"email,omitempty"`
-	Description string `json:"description,omitempty"`
-}
-
-type BimGroupInput struct {
-	IamId       string `json:"iamid" tfsdk:"iamid"`
-	Name        string `json:"name"`
-	Email       string `json:"email,omitempty"`
-	Description string `json:"description,omitempty"`
-}
-
-type BimGroup struct {
-	BimGroupInput
-	Id             int                    `json:"id"`
-	Authorizations map[string]interface{} `json:"authorizations,omitempty"`
-	CreatedAt      time.Time              `json:"createdAt"`
-	UpdatedAt      time.Time              `json:"updatedAt"`
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
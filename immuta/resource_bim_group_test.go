@@ -3,8 +3,10 @@ package immuta
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
 )
 
 const testBimGroupId = "tf_test_group_acc"
@@ -67,3 +69,47 @@ func testAccBimGroupConfigBasic(description string) string {
 	}
 `
 }
+
+func TestBimGroup_lifecycle(t *testing.T) {
+	l := newLifecycle[BimGroupResourceModel](t, NewBimGroupResource())
+
+	created := l.create(testBimGroupModel(testBimGroupDescription))
+	id64, _ := created.Id.ValueBigFloat().Int64()
+	id := int(id64)
+
+	read := l.read(created)
+	if read.Name.ValueString() != testBimGroupName || read.Description.ValueString() != testBimGroupDescription {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	planned := testBimGroupModel(testBimGroupUpdatedDescription)
+	planned.Id = read.Id
+	updated := l.update(read, planned)
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if state.Groups[id].Description != testBimGroupUpdatedDescription {
+			t.Errorf("expected the update to reach Immuta, got %q", state.Groups[id].Description)
+		}
+		state.Groups[id].Email = "changed@test.com"
+	})
+	drifted := l.read(updated)
+	if drifted.Email.ValueString() != "changed@test.com" {
+		t.Errorf("expected read to pick up the drifted email, got %q", drifted.Email.ValueString())
+	}
+
+	l.delete(drifted)
+	if l.read(drifted) != nil {
+		t.Error("expected a deleted group to be removed from the state")
+	}
+}
+
+func testBimGroupModel(description string) *BimGroupResourceModel {
+	return &BimGroupResourceModel{
+		Id:             types.NumberUnknown(),
+		IamId:          types.StringValue(testBimGroupIamId),
+		Name:           types.StringValue(testBimGroupName),
+		Email:          types.StringValue(testBimGroupEmail),
+		Authorizations: types.MapNull(types.StringType),
+		Description:    types.StringValue(description),
+	}
+}
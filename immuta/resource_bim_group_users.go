@@ -3,8 +3,6 @@ package immuta
 import (
 	"context"
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -25,7 +23,7 @@ func NewBimGroupUsersResource() resource.Resource {
 
 // BimGroupUsersResource defines the resource implementation.
 type BimGroupUsersResource struct {
-	client *client.ImmutaClient
+	bimGroups client.BimGroupsService
 }
 
 type BimGroupUsersResourceModel struct {
@@ -86,21 +84,24 @@ func (r *BimGroupUsersResource) Configure(ctx context.Context, req resource.Conf
 		return
 	}
 
-	immutaClient, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *immutaClient.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = immutaClient
+	r.bimGroups = immutaClient.BimGroups()
 }
 
 func (r *BimGroupUsersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group_users", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupUsersResourceModel
 
 	// Read Terraform prior state data into the model
@@ -112,7 +113,7 @@ func (r *BimGroupUsersResource) Read(ctx context.Context, req resource.ReadReque
 
 	readingFailedErrorMessage := "Error reading BimGroupUsers"
 
-	doesExist, err := r.ConfirmGroupExists(data.Id.String()) // we need to make sure the group does exist
+	doesExist, err := r.bimGroups.Exists(ctx, data.Id.String()) // we need to make sure the group does exist
 	if err != nil {
 		resp.Diagnostics.AddError(readingFailedErrorMessage, err.Error())
 		return
@@ -126,7 +127,7 @@ func (r *BimGroupUsersResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
-	bimGroupUsersResponse, err := r.GetBimGroupUsers(data.Id.String())
+	bimGroupUsersResponse, err := r.bimGroups.ListUsers(ctx, data.Id.String())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			readingFailedErrorMessage,
@@ -163,6 +164,9 @@ func (r *BimGroupUsersResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *BimGroupUsersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group_users", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupUsersResourceModel
 
 	// Read Terraform plan data into the model
@@ -182,7 +186,7 @@ func (r *BimGroupUsersResource) Create(ctx context.Context, req resource.CreateR
 		groupId := (users[0].Group.String())
 
 		// Validate the group exist and all users are ading to the same group
-		doesExist, err := r.ConfirmGroupExists(groupId)
+		doesExist, err := r.bimGroups.Exists(ctx, groupId)
 		if !doesExist {
 			if err != nil {
 				resp.Diagnostics.AddError(creatingFailedErrorMessage, fmt.Sprintf("Error reading the group with ID[%s]. %s", groupId, err))
@@ -200,10 +204,10 @@ func (r *BimGroupUsersResource) Create(ctx context.Context, req resource.CreateR
 
 		// add all users to the group
 		for idx, user := range users {
-			userInput := UserInput{}
+			userInput := client.UserInput{}
 			userInput.UserId = user.UserId.ValueString()
 			userInput.IamId = user.IamId.ValueString()
-			groupUserResponse, err := r.AddUserToGroup(user.Group.String(), userInput)
+			groupUserResponse, err := r.bimGroups.AddUser(ctx, user.Group.String(), userInput)
 			if err != nil {
 				resp.Diagnostics.AddError(creatingFailedErrorMessage, fmt.Sprintf("Error adding user [%s] to group: %s", user.UserId.ValueString(), err))
 				return
@@ -230,6 +234,9 @@ func (r *BimGroupUsersResource) Create(ctx context.Context, req resource.CreateR
 }
 
 func (r *BimGroupUsersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group_users", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupUsersResourceModel
 
 	// Read Terraform prior state data into the model
@@ -246,7 +253,7 @@ func (r *BimGroupUsersResource) Delete(ctx context.Context, req resource.DeleteR
 			return
 		}
 		for _, user := range users {
-			err := r.RemoveUserFromGroup(user.Group.String(), user.Id.String())
+			err := r.bimGroups.RemoveUser(ctx, user.Group.String(), user.Id.String())
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error deleting BimGroupUsers.",
@@ -260,6 +267,9 @@ func (r *BimGroupUsersResource) Delete(ctx context.Context, req resource.DeleteR
 
 // Update group details
 func (r *BimGroupUsersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_group_users", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimGroupUsersResourceModel
 
 	// Read Terraform plan data into the model
@@ -285,7 +295,7 @@ func (r *BimGroupUsersResource) Update(ctx context.Context, req resource.UpdateR
 	}
 	existingUsersMap := make(map[string]UserAttribute)
 
-	bimGroupUsersResponse, err := r.GetBimGroupUsers(groupId) // Fetch current users list
+	bimGroupUsersResponse, err := r.bimGroups.ListUsers(ctx, groupId) // Fetch current users list
 	if err != nil {
 		resp.Diagnostics.AddError(
 			updatingFailedErrorMessage,
@@ -301,7 +311,7 @@ func (r *BimGroupUsersResource) Update(ctx context.Context, req resource.UpdateR
 			if _, ok := newUsersMap[bimGroupUser.UserId]; ok {
 				existingUsersMap[bimGroupUser.UserId] = existingUser
 			} else {
-				err := r.RemoveUserFromGroup(existingUser.Group.String(), existingUser.Id.String())
+				err := r.bimGroups.RemoveUser(ctx, existingUser.Group.String(), existingUser.Id.String())
 				if err != nil {
 					resp.Diagnostics.AddError(
 						updatingFailedErrorMessage,
@@ -333,10 +343,10 @@ func (r *BimGroupUsersResource) Update(ctx context.Context, req resource.UpdateR
 					)
 					return
 				}
-				userInput := UserInput{}
+				userInput := client.UserInput{}
 				userInput.UserId = newUser.UserId.ValueString()
 				userInput.IamId = newUser.IamId.ValueString()
-				groupUserResponse, err := r.AddUserToGroup(groupId, userInput)
+				groupUserResponse, err := r.bimGroups.AddUser(ctx, groupId, userInput)
 				if err != nil {
 					resp.Diagnostics.AddError(updatingFailedErrorMessage, fmt.Sprintf("Error add a user to gorup: %s", err))
 					return
@@ -361,39 +371,14 @@ func (r *BimGroupUsersResource) Update(ctx context.Context, req resource.UpdateR
 }
 
 func (r *BimGroupUsersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
-
-// CRUD methods
+	ctx, span := startOperation(ctx, "immuta_bim_group_users", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
 
-func (r *BimGroupUsersResource) GetBimGroupUsers(groupId string) (bimGroupUsersResponse *BimGroupUsers, err error) {
-	err = r.client.Get(fmt.Sprintf("/bim/group/%s/user", groupId), "", nil, &bimGroupUsersResponse)
-	return
-}
-
-func (r *BimGroupUsersResource) AddUserToGroup(groupId string, userInput UserInput) (groupUserResponse *GroupUserResponse, err error) {
-	err = r.client.Post(fmt.Sprintf("/bim/group/%s/user", groupId), "", userInput, &groupUserResponse)
-	return
-}
-
-func (r *BimGroupUsersResource) RemoveUserFromGroup(groupId string, groupUserId string) (err error) {
-	err = r.client.Delete(fmt.Sprintf("/bim/group/%s/user/%s", groupId, groupUserId), "", nil, nil)
-	return
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
 // helper methods
 
-func (r *BimGroupUsersResource) ConfirmGroupExists(groupId string) (doesExist bool, err error) {
-	err = r.client.Get(fmt.Sprintf("/bim/group/%s", groupId), "", nil, nil)
-	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			return false, nil
-		}
-		return false, err
-	}
-	return true, nil
-}
-
 func UserAttributeSetFromGo(ctx context.Context, users []UserAttribute) (types.Set, diag.Diagnostics) {
 	userTypes := map[string]attr.Type{
 		"group":  types.NumberType,
@@ -405,7 +390,7 @@ func UserAttributeSetFromGo(ctx context.Context, users []UserAttribute) (types.S
 	return usersSet, diags
 }
 
-func BimGroupUserToUserAttribute(bimGroupUser BimGroupUser) UserAttribute {
+func BimGroupUserToUserAttribute(bimGroupUser client.BimGroupUser) UserAttribute {
 	user := UserAttribute{}
 	user.Group = intToNumberValue(bimGroupUser.Group)
 	user.Id = intToNumberValue(bimGroupUser.Id)
@@ -413,52 +398,3 @@ func BimGroupUserToUserAttribute(bimGroupUser BimGroupUser) UserAttribute {
 	user.IamId = types.StringValue(bimGroupUser.IamId)
 	return user
 }
-
-// Domain-specific methods
-
-type BimGroupUserProfile struct {
-	Id              int         `json:"id"`
-	Name            string      `json:"name,omitempty"`
-	Email           string      `json:"email,omitempty"`
-	Phone           string      `json:"phone,omitempty"`
-	About           string      `json:"about,omitempty"`
-	Location        string      `json:"location,omitempty"`
-	Organization    string      `json:"organization,omitempty"`
-	Position        string      `json:"position,omitempty"`
-	Preferences     interface{} `json:"preferences,omitempty"`
-	ExternalUserIds interface{} `json:"externalUserIds,omitempty"`
-	Scim            interface{} `json:"scim,omitempty"`
-	SystemGenerated bool        `json:"systemGenerated"`
-	CreatedAt       time.Time   `json:"createdAt"`
-	UpdatedAt       time.Time   `json:"updatedAt"`
-}
-
-type BimGroupUser struct {
-	Id        int                 `json:"id"`
-	Group     int                 `json:"group"`
-	Profile   BimGroupUserProfile `json:"profile"`
-	UserId    string              `json:"userid"`
-	Uid       int                 `json:"uid"`
-	IamId     string              `json:"iamid"`
-	Disabled  bool                `json:"disabled"`
-	CreatedAt time.Time           `json:"createdAt"`
-	UpdatedAt time.Time           `json:"updatedAt"`
-}
-
-type BimGroupUsers struct {
-	Count int            `json:"count"`
-	Hits  []BimGroupUser `json:"hits"`
-}
-
-type UserInput struct {
-	UserId string `json:"userid"`
-	IamId  string `json:"iamid"`
-}
-
-type GroupUserResponse struct {
-	Id        int       `json:"id"`
-	Group     int       `json:"group"`
-	Profile   int       `json:"profile"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-}
@@ -1,10 +1,14 @@
 package immuta
 
 import (
+	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
 )
 
 const testUser1UserId = "tf_acc_test_user1@instacart.com"
@@ -101,3 +105,57 @@ func testAccBimGroupUsersConfigBasic(users []string) string {
 		}
 	`
 }
+
+func TestBimGroupUsers_lifecycle(t *testing.T) {
+	l := newLifecycle[BimGroupUsersResourceModel](t, NewBimGroupUsersResource())
+	var groupId int
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		groupId = state.NextId()
+		state.Groups[groupId] = &client.BimGroup{Id: groupId}
+		state.Groups[groupId].Name = testBimGroupName
+	})
+
+	created := l.create(testBimGroupUsersModel(t, groupId, testUser1UserId))
+	if created.Id.String() != intToNumberValue(groupId).String() {
+		t.Errorf("expected the group id to be used as the id, got %s", created.Id)
+	}
+
+	planned := testBimGroupUsersModel(t, groupId, testUser1UserId, testUser2UserId)
+	planned.Id = created.Id
+	updated := l.update(created, planned)
+	if read := l.read(updated); len(read.Users.Elements()) != 2 {
+		t.Errorf("expected 2 users after update, got %d", len(read.Users.Elements()))
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.GroupUsers[groupId] = state.GroupUsers[groupId][1:]
+	})
+	drifted := l.read(updated)
+	if len(drifted.Users.Elements()) != 1 {
+		t.Errorf("expected read to pick up the removed user, got %d users", len(drifted.Users.Elements()))
+	}
+
+	l.delete(drifted)
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if len(state.GroupUsers[groupId]) != 0 {
+			t.Errorf("expected delete to remove every user, %d left", len(state.GroupUsers[groupId]))
+		}
+	})
+}
+
+func testBimGroupUsersModel(t *testing.T, groupId int, userIds ...string) *BimGroupUsersResourceModel {
+	users := make([]UserAttribute, 0, len(userIds))
+	for _, userId := range userIds {
+		users = append(users, UserAttribute{
+			Group:  intToNumberValue(groupId),
+			Id:     types.NumberUnknown(),
+			UserId: types.StringValue(userId),
+			IamId:  types.StringValue("immuta"),
+		})
+	}
+	usersSet, diags := UserAttributeSetFromGo(context.Background(), users)
+	if diags.HasError() {
+		t.Fatalf("could not build users: %v", diags)
+	}
+	return &BimGroupUsersResourceModel{Id: types.NumberUnknown(), Users: usersSet}
+}
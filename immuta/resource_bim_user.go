@@ -20,7 +20,7 @@ func NewBimUserResource() resource.Resource {
 
 // BimUserResource defines the resource implementation.
 type BimUserResource struct {
-	client *client.ImmutaClient
+	bimUsers client.BimUsersService
 }
 
 // BimUserResourceModel describes the resource data model.
@@ -77,21 +77,24 @@ func (r *BimUserResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.bimUsers = immutaClient.BimUsers()
 }
 
 func (r *BimUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_user", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimUserResourceModel
 
 	// Read Terraform plan data into the model
@@ -105,16 +108,16 @@ func (r *BimUserResource) Create(ctx context.Context, req resource.CreateRequest
 		data.Name = data.Userid
 	}
 
-	bimUserInput := BimUserInput{
+	bimUserInput := client.BimUserInput{
 		Userid:   data.Userid.ValueString(),
 		Password: data.Password.ValueString(),
-		Profile: BimUserProfileInput{
+		Profile: client.BimUserProfileInput{
 			Name:  data.Name.ValueString(),
 			Email: data.Email.ValueString(),
 		},
 	}
 
-	bimUserResponse, err := r.CreateBimUser(bimUserInput)
+	bimUserResponse, err := r.bimUsers.Create(ctx, bimUserInput)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating BimUser",
@@ -124,10 +127,10 @@ func (r *BimUserResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	if data.SnowflakeUser.ValueString() != "" {
-		bimUserProfile := BimUserProfile{}
+		bimUserProfile := client.BimUserProfile{}
 		bimUserProfile.ExternalUserIds.SnowflakeUser = data.SnowflakeUser.ValueString()
 
-		_, err := r.UpdateBimUserProfile(bimUserInput.Userid, &bimUserProfile)
+		_, err := r.bimUsers.UpdateProfile(ctx, bimUserInput.Userid, &bimUserProfile)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error creating BimUserProfile",
@@ -144,6 +147,9 @@ func (r *BimUserResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *BimUserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_user", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimUserResourceModel
 
 	// Read Terraform prior state data into the model
@@ -153,7 +159,12 @@ func (r *BimUserResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	bimUserResponse, err := r.GetBimUser(data.Id.ValueString())
+	bimUserResponse, err := r.bimUsers.Get(ctx, data.Id.ValueString())
+	if client.IsNotFound(err) {
+		// User no longer exists, remove from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading BimUser",
@@ -169,6 +180,10 @@ func (r *BimUserResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	// the userid is not known after an import until it has been read
+	if data.Userid.ValueString() != bimUserResponse.Userid {
+		data.Userid = types.StringValue(bimUserResponse.Userid)
+	}
 	if data.Name.ValueString() != bimUserResponse.Profile.Name {
 		data.Name = types.StringValue(bimUserResponse.Profile.Name)
 	}
@@ -185,6 +200,9 @@ func (r *BimUserResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 // Update updates user profile details
 func (r *BimUserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_user", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimUserResourceModel
 
 	// Read Terraform plan data into the model
@@ -205,11 +223,11 @@ func (r *BimUserResource) Update(ctx context.Context, req resource.UpdateRequest
 		data.Name = data.Userid
 	}
 
-	bimUserProfile := BimUserProfile{}
+	bimUserProfile := client.BimUserProfile{}
 	bimUserProfile.Email = data.Email.ValueString()
 	bimUserProfile.ExternalUserIds.SnowflakeUser = data.SnowflakeUser.ValueString()
 
-	_, err := r.UpdateBimUserProfile(data.Id.ValueString(), &bimUserProfile)
+	_, err := r.bimUsers.UpdateProfile(ctx, data.Id.ValueString(), &bimUserProfile)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating BimUser",
@@ -223,6 +241,9 @@ func (r *BimUserResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (r *BimUserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_bim_user", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *BimUserResourceModel
 
 	// Read Terraform prior state data into the model
@@ -232,7 +253,7 @@ func (r *BimUserResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.DeleteBimUser(data.Id.ValueString())
+	err := r.bimUsers.Delete(ctx, data.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting BimUser",
@@ -243,75 +264,8 @@ func (r *BimUserResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *BimUserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
+	ctx, span := startOperation(ctx, "immuta_bim_user", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
 
-// CRUD methods
-
-func (r *BimUserResource) ListBimUsers() (bimUserResponse *BimUser, err error) {
-	err = r.client.Get("/bim/iam/bim/user", "", map[string]string{}, &bimUserResponse)
-	return
-}
-
-func (r *BimUserResource) GetBimUser(userid string) (bimUserResponse *BimUser, err error) {
-	err = r.client.Get("/bim/iam/bim/user/"+userid, "", map[string]string{}, &bimUserResponse)
-	return
-}
-
-func (r *BimUserResource) CreateBimUser(bimUser BimUserInput) (bimUserResponse *BimUserCreateResponse, err error) {
-	err = r.client.Post("/bim/iam/bim/user", "", bimUser, &bimUserResponse)
-	return
-}
-
-func (r *BimUserResource) DeleteBimUser(userid string) (err error) {
-	err = r.client.Delete("/bim/iam/bim/user/"+userid, "", nil, nil)
-	return
-}
-
-//func (a *BimUserResource) UpdateBimUser(userid string, profile *BimUserProfile) (bimUserResponse *BimUser, err error) {
-//	err = a.client.Put("/bim/iam/bim/user/"+userid+"/profile", "", profile, &profile)
-//	return
-//}
-
-func (r *BimUserResource) UpdateBimUserProfile(userid string, profile *BimUserProfile) (bimUserResponse *BimUser, err error) {
-	err = r.client.Put("/bim/iam/bim/user/"+userid+"/profile", "", profile, &profile)
-	return
-}
-
-// Domain specific types
-
-type BimUserInput struct {
-	Userid      string              `json:"userid"`
-	Password    string              `json:"password"`
-	Profile     BimUserProfileInput `json:"profile"`
-	Permissions []interface{}       `json:"permissions,omitempty"`
-}
-
-type BimUser struct {
-	Userid  string         `json:"userid"`
-	Iamid   string         `json:"iamid"`
-	Profile BimUserProfile `json:"profile"`
-}
-
-type BimUserProfileInput struct {
-	Name  string `json:"name,omitempty"`
-	Email string `json:"email,omitempty"`
-}
-
-type BimUserProfile struct {
-	BimUserProfileInput
-	ExternalUserIds struct {
-		SnowflakeUser string `json:"snowflakeUser,omitempty"`
-	} `json:"externalUserIds,omitempty"`
-}
-
-type BimUserCreateResponse struct {
-	NewUser struct {
-		BimUser
-	} `json:"newUser"`
-}
-
-type BimUsers struct {
-	Users []BimUser `json:"users"`
-	Count int       `json:"count"`
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
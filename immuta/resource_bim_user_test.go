@@ -1,8 +1,10 @@
 package immuta
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
 	"testing"
 )
 
@@ -98,3 +100,51 @@ func testAccBimUserConfigWithName() string {
 	}
 `
 }
+
+func TestBimUser_lifecycle(t *testing.T) {
+	l := newLifecycle[BimUserResourceModel](t, NewBimUserResource())
+
+	created := l.create(testBimUserModel("userabc"))
+	if created.Id.ValueString() != testBimUserId || created.Name.ValueString() != testBimUserId {
+		t.Errorf("expected the id and name to default to the userid, got %+v", created)
+	}
+
+	read := l.read(created)
+	if read.Email.ValueString() != testBimUserEmail || read.SnowflakeUser.ValueString() != "userabc" {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	planned := testBimUserModel("userxyz")
+	planned.Id = read.Id
+	planned.Name = read.Name
+	updated := l.update(read, planned)
+
+	imported := l.importState(testBimUserId)
+	if imported.Userid.ValueString() != testBimUserId || imported.SnowflakeUser.ValueString() != "userxyz" {
+		t.Errorf("unexpected state after import: %+v", imported)
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.Users[testBimUserId].Profile.Email = "changed@test.com"
+	})
+	drifted := l.read(updated)
+	if drifted.Email.ValueString() != "changed@test.com" {
+		t.Errorf("expected read to pick up the drifted email, got %q", drifted.Email.ValueString())
+	}
+
+	l.delete(drifted)
+	if l.read(drifted) != nil {
+		t.Error("expected a deleted user to be removed from the state")
+	}
+}
+
+func testBimUserModel(snowflakeUser string) *BimUserResourceModel {
+	return &BimUserResourceModel{
+		Id:            types.StringUnknown(),
+		Userid:        types.StringValue(testBimUserId),
+		Password:      types.StringValue(testBimUserPassword),
+		Name:          types.StringUnknown(),
+		Email:         types.StringValue(testBimUserEmail),
+		SnowflakeUser: types.StringValue(snowflakeUser),
+	}
+}
@@ -3,20 +3,27 @@ package immuta
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/immuta/terraform-provider-immuta/client"
 	"strings"
+	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DataSourceResource{}
 var _ resource.ResourceWithImportState = &DataSourceResource{}
+var _ resource.ResourceWithModifyPlan = &DataSourceResource{}
+var _ resource.ResourceWithValidateConfig = &DataSourceResource{}
+var _ resource.ResourceWithUpgradeState = &DataSourceResource{}
 
 func NewDataSourceResource() resource.Resource {
 	return &DataSourceResource{}
@@ -24,7 +31,8 @@ func NewDataSourceResource() resource.Resource {
 
 // DataSourceResource defines the resource implementation.
 type DataSourceResource struct {
-	client *client.ImmutaClient
+	dataSources client.DataSourcesService
+	features    client.FeatureChecker
 }
 
 // DataSourceResourceModel describes the resource data model.
@@ -32,10 +40,14 @@ type DataSourceResourceModel struct {
 	Id            types.String `tfsdk:"id"`
 	ConnectionKey types.String `tfsdk:"connection_key"`
 	NameTemplate  types.Object `tfsdk:"name_template"`
+	NamePreview   types.Object `tfsdk:"name_preview"`
 	Options       types.Object `tfsdk:"options"`
 	Owners        types.List   `tfsdk:"owners"`
 	// appended _details because "connection" is a reserved word in HCL
-	Connection types.Object `tfsdk:"connection_details"`
+	Connection        types.Object   `tfsdk:"connection_details"`
+	Sources           types.List     `tfsdk:"sources"`
+	WaitForCompletion types.Bool     `tfsdk:"wait_for_completion"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (*DataSourceResourceModel) NameTemplateAttributes() map[string]attr.Type {
@@ -62,30 +74,8 @@ func (*DataSourceResourceModel) OwnersAttributes() map[string]attr.Type {
 	}
 }
 
-func (m *DataSourceResourceModel) ConnectionAttributes() map[string]attr.Type {
-	return map[string]attr.Type{
-		"handler":                   types.StringType,
-		"hostname":                  types.StringType,
-		"port":                      types.NumberType,
-		"database":                  types.StringType,
-		"schema":                    types.StringType,
-		"username":                  types.StringType,
-		"authentication_method":     types.StringType,
-		"password":                  types.StringType,
-		"user_files":                types.ListType{ElemType: types.ObjectType{AttrTypes: m.UserFilesAttributes()}},
-		"connection_string_options": types.StringType,
-		"ssl":                       types.BoolType,
-		"warehouse":                 types.StringType,
-		"http_path":                 types.StringType,
-	}
-}
-
-func (*DataSourceResourceModel) UserFilesAttributes() map[string]attr.Type {
-	return map[string]attr.Type{
-		"key":       types.StringType,
-		"content":   types.StringType,
-		"file_name": types.StringType,
-	}
+func (*DataSourceResourceModel) ConnectionAttributes() map[string]attr.Type {
+	return connectionDetailsAttributeTypes()
 }
 
 func (r *DataSourceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -96,6 +86,8 @@ func (r *DataSourceResource) Schema(ctx context.Context, req resource.SchemaRequ
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Register a data source via the V2 API.",
+		// version 1 replaced the flat connection_details with an attribute per handler
+		Version: 1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": stringResourceId(),
@@ -110,21 +102,32 @@ func (r *DataSourceResource) Schema(ctx context.Context, req resource.SchemaRequ
 					"data_source_format": schema.StringAttribute{
 						Required:    true,
 						Description: "How the data source named will be formatted in Immuta.",
+						Validators:  []validator.String{nameTemplateFormat{required: []string{placeholderTableName}}},
 					},
 					"table_format": schema.StringAttribute{
 						Required:    true,
 						Description: "How the table named will be formatted in Immuta.",
+						Validators:  []validator.String{nameTemplateFormat{required: []string{placeholderTableName}}},
 					},
 					"schema_format": schema.StringAttribute{
 						Required:    true,
 						Description: "How the schema named will be formatted in Immuta.",
+						Validators: []validator.String{nameTemplateFormat{
+							required:  []string{placeholderSchema},
+							forbidden: []string{placeholderTableName},
+						}},
 					},
 					"schema_project_name_format": schema.StringAttribute{
 						Required:    true,
 						Description: "How the schema project named will be formatted in Immuta.",
+						Validators: []validator.String{nameTemplateFormat{
+							required:  []string{placeholderSchema},
+							forbidden: []string{placeholderTableName},
+						}},
 					},
 				},
 			},
+			"name_preview": namePreviewAttribute(),
 			"options": schema.SingleNestedAttribute{
 				Optional:    true,
 				Description: "The options for the data source.",
@@ -163,81 +166,19 @@ func (r *DataSourceResource) Schema(ctx context.Context, req resource.SchemaRequ
 			// appended _details because "connection" is a reserved word in HCL
 			"connection_details": schema.SingleNestedAttribute{
 				Required:    true,
-				Description: "The connection details for the data source.",
-				Attributes: map[string]schema.Attribute{
-					"handler": schema.StringAttribute{
-						Required:    true,
-						Description: "The handler for the data source, one of [Snowflake, Databricks, Trino etc.].",
-					},
-					"hostname": schema.StringAttribute{
-						Required:    true,
-						Description: "The hostname for the data source.",
-					},
-					"port": schema.NumberAttribute{
-						Required:    true,
-						Description: "The port to which to connect.",
-					},
-					"database": schema.StringAttribute{
-						Required:    true,
-						Description: "The database containing the data source.",
-					},
-					"schema": schema.StringAttribute{
-						Optional:    true,
-						Description: "The schema containing the data source.",
-					},
-					"username": schema.StringAttribute{
-						Required:    true,
-						Description: "The username with which to connect.",
-					},
-					"authentication_method": schema.StringAttribute{
-						Optional:    true,
-						Description: "The authentication method for the data source.",
-					},
-					"password": schema.StringAttribute{
-						Optional:    true,
-						Description: "The password for the data source.",
-						Sensitive:   true,
-					},
-					"user_files": schema.ListNestedAttribute{
-						Optional:    true,
-						Description: "The user files for the data source.",
-						NestedObject: schema.NestedAttributeObject{
-							Attributes: map[string]schema.Attribute{
-								"key": schema.StringAttribute{
-									Required:    true,
-									Description: "The key for the user file.",
-								},
-								"content": schema.StringAttribute{
-									Required:    true,
-									Description: "The base64 encoded content of the user file.",
-									Sensitive:   true,
-								},
-								"file_name": schema.StringAttribute{
-									Required:    true,
-									Description: "The file name for the user file, to be displayed in UI.",
-								},
-							},
-						},
-					},
-					"connection_string_options": schema.StringAttribute{
-						Optional:    true,
-						Description: "The connection string options for the data source.",
-					},
-					"ssl": schema.BoolAttribute{
-						Optional:    true,
-						Description: "true|false Whether or not to use SSL for the data source.",
-					},
-					"warehouse": schema.StringAttribute{
-						Optional:    true,
-						Description: "[Snowflake] The warehouse for the ingestion.",
-					},
-					"http_path": schema.StringAttribute{
-						Optional:    true,
-						Description: "[Databricks] The HTTP path for the cluster used to ingest.",
-					},
-				},
+				Description: "The connection details for the data source, set exactly one of the handlers.",
+				Attributes:  connectionDetailsAttributes(),
+			},
+			"sources": sourcesAttribute(),
+			"wait_for_completion": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether to wait for Immuta to finish detecting the tables of the connection before the apply moves " +
+					"on to dependent resources. The wait counts against the create and update timeouts.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -247,49 +188,72 @@ func (r *DataSourceResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.dataSources = immutaClient.DataSources()
+	r.features = immutaClient
 }
 
 func (r *DataSourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_data_source", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *DataSourceResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
-	dataSourceInput := DataSourceInput{}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	dataSourceInput := client.DataSourceInput{}
 	if diags := dataSourceInputFromResourceData(ctx, *data, &dataSourceInput); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	_, err := r.UpsertDataSource(dataSourceInput)
+	resp.Diagnostics.Append(featureWarning(r.features, client.FeatureV2Data)...)
+	dataSourceResponse, err := r.dataSources.Upsert(ctx, dataSourceInput)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating data source", err.Error())
+		resp.Diagnostics.AddError(clientErrorSummary("Error creating data source", err), err.Error())
 		return
 	}
 
 	// todo once can figure out a gettable ID, change to this?
 	data.Id = data.ConnectionKey
+	data.NamePreview = namePreview(ctx, *data)
+	if data.WaitForCompletion.ValueBool() && dataSourceResponse.DetectionRunning {
+		resp.Diagnostics.Append(r.waitForCompletion(ctx, dataSourceInput)...)
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-// Cannot currently read all attributes of a data source created via the V2 API, so we just check if the data source
-// still exists and assume the attributes are unchanged
-// todo update once a full read method is possible
+// Read removes the data source from the state once its connection is no longer registered. When the tenant serves
+// the connection's configuration the state is refreshed with it, so changes made outside Terraform, e.g. in the UI,
+// show up as a diff.
 func (r *DataSourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_data_source", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *DataSourceResourceModel
 
 	// Read Terraform prior state data into the model
@@ -299,20 +263,54 @@ func (r *DataSourceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	doesExist, err := r.ConfirmDataSourceExists(data.ConnectionKey.ValueString())
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	exists, err := r.dataSources.Exists(ctx, data.ConnectionKey.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading data source", err.Error())
+		resp.Diagnostics.AddError(clientErrorSummary("Error reading data source", err), err.Error())
 		return
 	}
-	if !doesExist {
+	if !exists {
+		// Data source no longer exists, remove from state
 		resp.State.RemoveResource(ctx)
+		return
 	}
 
+	// reading the registered configuration back is not in Immuta's published API reference, a tenant that does
+	// not serve it keeps the prior state
+	dataSource, err := r.dataSources.Get(ctx, data.ConnectionKey.ValueString())
+	if client.IsNotFound(err) || client.IsMethodNotAllowed(err) {
+		resp.Diagnostics.AddWarning(
+			"Drift detection unavailable",
+			fmt.Sprintf("Immuta does not serve the configuration of connection %s, changes made outside Terraform will not show up in the plan: %s", data.ConnectionKey.ValueString(), err),
+		)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(clientErrorSummary("Error reading data source", err), err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resourceDataFromDataSourceInput(ctx, *dataSource, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.NamePreview = namePreview(ctx, *data)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DataSourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_data_source", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *DataSourceResourceModel
 
 	// Read Terraform plan data into the model
@@ -322,23 +320,39 @@ func (r *DataSourceResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	dataSourceInput := DataSourceInput{}
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	dataSourceInput := client.DataSourceInput{}
 	if diags := dataSourceInputFromResourceData(ctx, *data, &dataSourceInput); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	_, err := r.UpsertDataSource(dataSourceInput)
+	resp.Diagnostics.Append(featureWarning(r.features, client.FeatureV2Data)...)
+	dataSourceResponse, err := r.dataSources.Upsert(ctx, dataSourceInput)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating data source", err.Error())
+		resp.Diagnostics.AddError(clientErrorSummary("Error updating data source", err), err.Error())
 		return
 	}
 
+	data.NamePreview = namePreview(ctx, *data)
+	if data.WaitForCompletion.ValueBool() && dataSourceResponse.DetectionRunning {
+		resp.Diagnostics.Append(r.waitForCompletion(ctx, dataSourceInput)...)
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DataSourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_data_source", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *DataSourceResourceModel
 
 	// Read Terraform prior state data into the model
@@ -348,24 +362,229 @@ func (r *DataSourceResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	err := r.DeleteDataSource(data.ConnectionKey.ValueString())
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	err := r.dataSources.Delete(ctx, data.ConnectionKey.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting data source", err.Error())
+		resp.Diagnostics.AddError(clientErrorSummary("Error deleting data source", err), err.Error())
 		return
 	}
 }
 
 func (r *DataSourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := startOperation(ctx, "immuta_data_source", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
+
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// the id is the connection key, which Read looks the data source up by
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("connection_key"), req.ID)...)
+}
+
+func (r *DataSourceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var connection types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("connection_details"), &connection)...)
+	if resp.Diagnostics.HasError() || connection.IsNull() || connection.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateConnectionDetails(connection)...)
+}
+
+// UpgradeState moves the flat connection_details of version 0 into the attribute of their handler
+func (r *DataSourceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	current := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	priorSchema := current.Schema
+	priorSchema.Version = 0
+	priorSchema.Attributes = map[string]schema.Attribute{}
+	for name, attribute := range current.Schema.Attributes {
+		priorSchema.Attributes[name] = attribute
+	}
+	priorSchema.Attributes["connection_details"] = schema.ObjectAttribute{
+		Required:       true,
+		AttributeTypes: dataSourceConnectionAttributesV0(),
+	}
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &priorSchema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var data *DataSourceResourceModel
+				resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				// the tfsdk tags of the client's connection describe the flat connection_details of version 0
+				flat := client.DataSourceConnection{}
+				resp.Diagnostics.Append(data.Connection.As(ctx, &flat, defaultToZeroValue())...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				connection, diags := connectionFromAPI(flat)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				data.Connection = refreshedValue(ctx, types.ObjectNull(data.ConnectionAttributes()), connection).(types.Object)
+				data.NamePreview = namePreview(ctx, *data)
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
+}
+
+// ModifyPlan previews the apply with a dry run, warning about every Immuta data source it would create, update or delete
+func (r *DataSourceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx, span := startOperation(ctx, "immuta_data_source", operationPlan)
+	defer endOperation(span, &resp.Diagnostics)
+
+	// the provider is not configured yet when the configuration is only validated
+	if r.dataSources == nil {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		var state *DataSourceResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		preview, err := r.dataSources.DryRunDelete(ctx, state.ConnectionKey.ValueString())
+		if client.IsNotFound(err) {
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddWarning("Could not preview the data source changes", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(dryRunWarnings(preview)...)
+		return
+	}
+
+	// nothing to preview when the plan leaves the data source as it is
+	if !req.State.Raw.IsNull() && req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var data *DataSourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// values that are only known after other resources have been applied cannot be sent to Immuta yet
+	if !isFullyKnown(ctx, data.ConnectionKey, data.NameTemplate, data.Options, data.Owners, data.Connection, data.Sources) {
+		return
+	}
+
+	dataSourceInput := client.DataSourceInput{}
+	if diags := dataSourceInputFromResourceData(ctx, *data, &dataSourceInput); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	preview, err := r.dataSources.DryRun(ctx, dataSourceInput)
+	if client.IsValidation(err) {
+		resp.Diagnostics.AddError("Immuta rejected the data source", err.Error())
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddWarning("Could not preview the data source changes", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(dryRunWarnings(preview)...)
 }
 
 // helper functions
 
-func dataSourceInputFromResourceData(ctx context.Context, data DataSourceResourceModel, input *DataSourceInput) diag.Diagnostics {
+// waitForCompletion waits for the table detection started by an upsert to finish. The upsert already succeeded
+// when waiting fails, the error is reported while the caller still saves the state so the registered data source
+// is not lost.
+func (r *DataSourceResource) waitForCompletion(ctx context.Context, dataSource client.DataSourceInput) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if err := waitForDetection(ctx, r.dataSources, dataSource); err != nil {
+		diags.AddError(
+			clientErrorSummary("Error waiting for the data source detection", err),
+			fmt.Sprintf("%s is registered in Immuta and saved to the state, but its table detection did not complete: %s", dataSource.ConnectionKey, err),
+		)
+	}
+	return diags
+}
+
+// dataSourcePollInterval is how often the detection is checked while waiting for it to complete
+var dataSourcePollInterval = 10 * time.Second
+
+// waitForDetection repeats the registration as a dry run, which changes nothing, until Immuta no longer reports the
+// detection as running or the context is done. A dry run that would still create or update data sources means the
+// registration is not what was just sent, so there is nothing to wait for.
+func waitForDetection(ctx context.Context, dataSources client.DataSourcesService, dataSource client.DataSourceInput) error {
+	start := time.Now()
+	for {
+		timer := time.NewTimer(dataSourcePollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("detection of %s has not finished after %s: %w", dataSource.ConnectionKey, time.Since(start).Round(time.Second), ctx.Err())
+		case <-timer.C:
+		}
+
+		preview, err := dataSources.DryRun(ctx, dataSource)
+		if err != nil {
+			return err
+		}
+		if len(preview.Creating)+len(preview.Updating) > 0 {
+			return fmt.Errorf("the registration of %s changed while waiting for its detection, Immuta would still create %v and update %v", dataSource.ConnectionKey, preview.Creating, preview.Updating)
+		}
+		if !preview.DetectionRunning {
+			return nil
+		}
+
+		tflog.Info(ctx, "Waiting for Immuta to finish detecting the data source tables", map[string]interface{}{
+			"connection_key": dataSource.ConnectionKey,
+			"elapsed":        time.Since(start).Round(time.Second).String(),
+		})
+	}
+}
+
+// dryRunWarnings lists the data sources a dry run reported as changing, so nobody is surprised by mass deletions
+func dryRunWarnings(preview *client.DataSourceResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	changes := []struct {
+		verb  string
+		names []string
+	}{
+		{"create", preview.Creating},
+		{"update", preview.Updating},
+		{"delete", preview.Deleting},
+	}
+	for _, change := range changes {
+		if len(change.names) == 0 {
+			continue
+		}
+		diags.AddWarning(
+			fmt.Sprintf("Immuta will %s %d data source(s)", change.verb, len(change.names)),
+			fmt.Sprintf("Applying this plan will %s the following data sources in Immuta:\n  - %s", change.verb, strings.Join(change.names, "\n  - ")),
+		)
+	}
+
+	return diags
+}
+
+func dataSourceInputFromResourceData(ctx context.Context, data DataSourceResourceModel, input *client.DataSourceInput) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	input.ConnectionKey = data.ConnectionKey.ValueString()
-	nameTemplate := DataSourceNameTemplate{}
+	nameTemplate := client.DataSourceNameTemplate{}
 	if conversionDiag := data.NameTemplate.As(ctx, &nameTemplate, basetypes.ObjectAsOptions{
 		UnhandledNullAsEmpty:    false,
 		UnhandledUnknownAsEmpty: false,
@@ -375,7 +594,7 @@ func dataSourceInputFromResourceData(ctx context.Context, data DataSourceResourc
 	input.NameTemplate = nameTemplate
 
 	if !data.Options.IsNull() && !data.Options.IsUnknown() {
-		options := DataSourceOptions{}
+		options := client.DataSourceOptions{}
 		if conversionDiag := data.Options.As(ctx, &options, defaultToZeroValue()); conversionDiag.HasError() {
 			return conversionDiag
 		}
@@ -383,108 +602,80 @@ func dataSourceInputFromResourceData(ctx context.Context, data DataSourceResourc
 	}
 
 	if !data.Owners.IsNull() && !data.Owners.IsUnknown() {
-		var owners []DataSourceOwners
+		var owners []client.DataSourceOwners
 		if conversionDiag := data.Owners.ElementsAs(ctx, &owners, false); conversionDiag.HasError() {
 			return conversionDiag
 		}
 		input.Owners = owners
 	}
 
-	connection := DataSourceConnection{}
-	if conversionDiag := data.Connection.As(ctx, &connection, defaultToZeroValue()); conversionDiag.HasError() {
+	connection, conversionDiag := connectionFromResourceData(data.Connection)
+	if conversionDiag.HasError() {
 		return conversionDiag
 	}
 	input.Connection = connection
 
-	return diags
-}
-
-// CRUD methods
-
-func (r *DataSourceResource) UpsertDataSource(dataSource DataSourceInput) (dataSourceResponse DataSourceResponse, err error) {
-	err = r.client.PostWithQuery("/api/v2/data", "", dataSource, map[string]string{"dryRun": "false"}, &dataSourceResponse)
-	return
-}
-
-func (r *DataSourceResource) DeleteDataSource(connectionKey string) (err error) {
-	err = r.client.Delete(fmt.Sprintf("/api/v2/data/%s", connectionKey), "", nil, nil)
-	return
-}
-
-func (r *DataSourceResource) ConfirmDataSourceExists(connectionKey string) (doesExist bool, err error) {
-	dataSourceResponse := DataSourceResponse{}
-	err = r.client.DeleteWithQuery(
-		fmt.Sprintf("/api/v2/data/%s", connectionKey),
-		"",
-		nil,
-		map[string]string{"dryRun": "true"},
-		&dataSourceResponse,
-	)
-	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			return false, nil
+	if !data.Sources.IsNull() && !data.Sources.IsUnknown() {
+		for _, element := range data.Sources.Elements() {
+			source := client.DataSourceSource{}
+			if conversionDiag := element.(types.Object).As(ctx, &source, defaultToZeroValue()); conversionDiag.HasError() {
+				return conversionDiag
+			}
+			input.Sources = append(input.Sources, source)
 		}
-		return false, err
 	}
-	return true, nil
-}
 
-// Domain specific types
-
-type DataSourceNameTemplate struct {
-	DataSourceFormat        string `json:"dataSourceFormat" tfsdk:"data_source_format"`
-	TableFormat             string `json:"tableFormat" tfsdk:"table_format"`
-	SchemaFormat            string `json:"schemaFormat" tfsdk:"schema_format"`
-	SchemaProjectNameFormat string `json:"schemaProjectNameFormat" tfsdk:"schema_project_name_format"`
-}
-
-type DataSourceOptions struct {
-	TableTags                     []string `json:"tableTags,omitempty" tfsdk:"table_tags"`
-	DisableSensitiveDataDiscovery bool     `json:"disableSensitiveDataDiscovery,omitempty" tfsdk:"disable_sensitive_data_discovery"`
+	return diags
 }
 
-type DataSourceOwners struct {
-	Type string `json:"type" tfsdk:"type"`
-	Name string `json:"name" tfsdk:"name"`
-	Iam  string `json:"iam,omitempty" tfsdk:"iam"`
-}
+// resourceDataFromDataSourceInput updates the model with the configuration Immuta reports for the connection
+func resourceDataFromDataSourceInput(ctx context.Context, input client.DataSourceInput, data *DataSourceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
 
-type DataSourceConnection struct {
-	Handler                 string      `json:"handler" tfsdk:"handler"`
-	Hostname                string      `json:"hostname" tfsdk:"hostname"`
-	Port                    int         `json:"port,omitempty" tfsdk:"port"`
-	Database                string      `json:"database" tfsdk:"database"`
-	Schema                  string      `json:"schema,omitempty" tfsdk:"schema"`
-	Username                string      `json:"username" tfsdk:"username"`
-	AuthenticationMethod    string      `json:"authenticationMethod,omitempty" tfsdk:"authentication_method"`
-	Password                string      `json:"password,omitempty" tfsdk:"password"`
-	UserFiles               []UserFiles `json:"userFiles,omitempty" tfsdk:"user_files"`
-	ConnectionStringOptions string      `json:"connectionStringOptions,omitempty" tfsdk:"connection_string_options"`
-	Ssl                     bool        `json:"ssl,omitempty" tfsdk:"ssl"`
-	Warehouse               string      `json:"warehouse,omitempty" tfsdk:"warehouse"`
-	HttpPath                string      `json:"httpPath,omitempty" tfsdk:"http_path"`
-}
+	nameTemplate, conversionDiag := types.ObjectValueFrom(ctx, data.NameTemplateAttributes(), input.NameTemplate)
+	diags.Append(conversionDiag...)
+	options, conversionDiag := types.ObjectValueFrom(ctx, data.OptionsAttributes(), input.Options)
+	diags.Append(conversionDiag...)
+	owners, conversionDiag := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: data.OwnersAttributes()}, input.Owners)
+	diags.Append(conversionDiag...)
+	sources, conversionDiag := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: data.SourcesAttributes()}, input.Sources)
+	diags.Append(conversionDiag...)
+	connection, conversionDiag := connectionFromAPI(input.Connection)
+	diags.Append(conversionDiag...)
+	if diags.HasError() {
+		return diags
+	}
+	// Immuta never returns the connection's secrets, keep the ones Terraform last sent
+	connection = withPriorSecrets(ctx, connection, data.Connection)
 
-type UserFiles struct {
-	Key      string `json:"key"`
-	Content  string `json:"content"`
-	FileName string `json:"fileName"`
-}
+	data.NameTemplate = refreshedValue(ctx, data.NameTemplate, nameTemplate).(types.Object)
+	data.Options = refreshedValue(ctx, data.Options, options).(types.Object)
+	data.Owners = refreshedValue(ctx, data.Owners, owners).(types.List)
+	data.Sources = refreshedValue(ctx, data.Sources, sources).(types.List)
+	data.Connection = refreshedValue(ctx, data.Connection, connection).(types.Object)
 
-type DataSourceInput struct {
-	ConnectionKey string                 `json:"connectionKey"`
-	NameTemplate  DataSourceNameTemplate `json:"nameTemplate,omitempty"`
-	Options       DataSourceOptions      `json:"options,omitempty"`
-	Owners        []DataSourceOwners     `json:"owners,omitempty"`
-	Connection    DataSourceConnection   `json:"connection"`
+	return diags
 }
 
-type DataSourceResponse struct {
-	DryRun           bool     `json:"dryRun"`
-	Creating         []string `json:"creating"`
-	Updating         []string `json:"updating"`
-	Deleting         []string `json:"deleting"`
-	NoChange         []string `json:"noChange"`
-	DetectionRunning bool     `json:"detectionRunning"`
-	TagsUpdated      bool     `json:"tagsUpdated"`
+// dataSourceConnectionAttributesV0 are the types of the flat connection_details of schema version 0
+func dataSourceConnectionAttributesV0() map[string]attr.Type {
+	return map[string]attr.Type{
+		"handler":               types.StringType,
+		"hostname":              types.StringType,
+		"port":                  types.NumberType,
+		"database":              types.StringType,
+		"schema":                types.StringType,
+		"username":              types.StringType,
+		"authentication_method": types.StringType,
+		"password":              types.StringType,
+		"user_files": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"key":       types.StringType,
+			"content":   types.StringType,
+			"file_name": types.StringType,
+		}}},
+		"connection_string_options": types.StringType,
+		"ssl":                       types.BoolType,
+		"warehouse":                 types.StringType,
+		"http_path":                 types.StringType,
+	}
 }
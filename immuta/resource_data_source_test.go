@@ -1,12 +1,33 @@
 package immuta
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"net/http"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 const testDataSourceDatabase = "TERRAFORM_INTEGRATION_TEST"
@@ -19,7 +40,7 @@ func TestAccDataSource_basic(t *testing.T) {
 			testAccPreCheck(t)
 			testAccDataSourcePreCheck(t)
 		},
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: testAccCassetteProviderFactories(t),
 		CheckDestroy:             testAccCheckDataSourceDestroy,
 		Steps: []resource.TestStep{
 			// test create and read
@@ -47,6 +68,15 @@ func TestAccDataSource_basic(t *testing.T) {
 }
 
 func testAccDataSourcePreCheck(t *testing.T) {
+	if testAccReplaying() {
+		// the connection details are only sent in request bodies, which replay does not compare
+		for _, name := range []string{"USERNAME", "PASSWORD", "HOST", "WAREHOUSE", "ROLE"} {
+			if os.Getenv("ACC_IMMUTA_SNOWFLAKE_"+name) == "" {
+				t.Setenv("ACC_IMMUTA_SNOWFLAKE_"+name, "replay")
+			}
+		}
+		return
+	}
 	if os.Getenv("ACC_IMMUTA_SNOWFLAKE_USERNAME") == "" {
 		t.Fatal("ACC_IMMUTA_SNOWFLAKE_USERNAME must be set for data source acceptance tests")
 	}
@@ -90,19 +120,888 @@ func testAccDataSourceConfig(tags []string) string {
 			schema_project_name_format = "tfacc::<database>.<schema>"
         }
 		connection_details = {
-			handler = "Snowflake"
-			hostname = "%[1]s"
-			port = 443
-			database = "%[2]s"
-			schema = "%[3]s"
-			username = "%[4]s"
-			password = "%[5]s"
-			warehouse = "%[6]s"
-			ssl = true
-			connection_string_options = "role=%[7]s"
+			snowflake = {
+				hostname = "%[1]s"
+				database = "%[2]s"
+				schema = "%[3]s"
+				username = "%[4]s"
+				password = "%[5]s"
+				warehouse = "%[6]s"
+				connection_string_options = "role=%[7]s"
+			}
 		}
 		options = {
 			table_tags = %[8]s
 		}
 	}`, host, testDataSourceDatabase, testDataSourceSchema, username, password, warehouse, role, tagsString, testDataSourceConnectionKey)
 }
+
+func TestDataSource_lifecycle(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+
+	created := l.create(testDataSourceModel(t, []string{"a"}))
+	if created.Id.ValueString() != testDataSourceConnectionKey {
+		t.Errorf("expected the connection key to be used as the id, got %s", created.Id)
+	}
+
+	planned := testDataSourceModel(t, []string{"a", "b"})
+	planned.Id = created.Id
+	updated := l.update(l.read(created), planned)
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		tags := state.DataSources[testDataSourceConnectionKey].Options.TableTags
+		if len(tags) != 2 {
+			t.Errorf("expected the update to reach Immuta, got tags %v", tags)
+		}
+	})
+
+	imported := l.importState(testDataSourceConnectionKey)
+	if imported == nil {
+		t.Fatal("expected the data source to be imported")
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		delete(state.DataSources, testDataSourceConnectionKey)
+	})
+	if l.read(updated) != nil {
+		t.Error("expected a data source deleted outside Terraform to be removed from the state")
+	}
+
+	created = l.create(testDataSourceModel(t, nil))
+	l.delete(created)
+	if l.read(created) != nil {
+		t.Error("expected a deleted data source to be removed from the state")
+	}
+}
+
+func TestDataSource_readWithoutDriftDetection(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	created := l.create(testDataSourceModel(t, []string{"a"}))
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.Unserved["GET /api/v2/data/{connectionKey}"] = true
+		state.DataSources[testDataSourceConnectionKey].Connection.Warehouse = "edited_warehouse"
+	})
+	refreshed, diags := l.tryRead(created)
+	if diags.HasError() || diags.WarningsCount() != 1 || diags.Warnings()[0].Summary() != "Drift detection unavailable" {
+		t.Fatalf("expected a warning that drift is not detected, got %v", diags)
+	}
+	if refreshed == nil || !l.state(refreshed).Raw.Equal(l.state(created).Raw) {
+		t.Errorf("expected the prior state to be kept, got %+v", refreshed)
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		delete(state.DataSources, testDataSourceConnectionKey)
+	})
+	if l.read(created) != nil {
+		t.Error("expected a data source deleted outside Terraform to still be removed from the state")
+	}
+}
+
+func TestDataSource_readDetectsDrift(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+
+	created := l.create(testDataSourceModel(t, []string{"a"}))
+	if refreshed := l.read(created); !l.state(refreshed).Raw.Equal(l.state(created).Raw) {
+		t.Errorf("expected an unchanged data source to read back as it was created, got %+v", refreshed)
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		dataSource := state.DataSources[testDataSourceConnectionKey]
+		dataSource.NameTemplate.TableFormat = "edited_<tablename>"
+		dataSource.Options = client.DataSourceOptions{TableTags: []string{"a", "ui"}, DisableSensitiveDataDiscovery: true}
+		dataSource.Owners = []client.DataSourceOwners{{Type: "group", Name: "stewards"}}
+		dataSource.Connection.Warehouse = "edited_warehouse"
+	})
+	refreshed := l.read(created)
+
+	nameTemplate := client.DataSourceNameTemplate{}
+	options := client.DataSourceOptions{}
+	for _, diags := range []diag.Diagnostics{
+		refreshed.NameTemplate.As(context.Background(), &nameTemplate, defaultToZeroValue()),
+		refreshed.Options.As(context.Background(), &options, defaultToZeroValue()),
+	} {
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+	}
+	if nameTemplate.TableFormat != "edited_<tablename>" {
+		t.Errorf("expected the name template to be refreshed, got %+v", nameTemplate)
+	}
+	if !reflect.DeepEqual(options.TableTags, []string{"a", "ui"}) || !options.DisableSensitiveDataDiscovery {
+		t.Errorf("expected the options to be refreshed, got %+v", options)
+	}
+	if len(refreshed.Owners.Elements()) != 1 {
+		t.Fatalf("expected the owners to be refreshed, got %s", refreshed.Owners)
+	}
+	owner := refreshed.Owners.Elements()[0].(types.Object).Attributes()
+	if owner["name"].(types.String).ValueString() != "stewards" {
+		t.Errorf("expected the owners to be refreshed, got %s", refreshed.Owners)
+	}
+	if !owner["iam"].IsNull() {
+		t.Errorf("expected attributes Immuta reports as empty to stay null, got %s", owner["iam"])
+	}
+	if warehouse := testSnowflakeAttribute(refreshed, "warehouse"); warehouse.ValueString() != "edited_warehouse" {
+		t.Errorf("expected the connection to be refreshed, got %s", warehouse)
+	}
+	if testSnowflakeAttribute(refreshed, "password").ValueString() != "tf_acc_password" {
+		t.Error("expected the password Immuta does not return to be kept")
+	}
+
+	imported := l.importState(testDataSourceConnectionKey)
+	if hostname := testSnowflakeAttribute(imported, "hostname"); hostname.ValueString() != "example.snowflakecomputing.com" {
+		t.Errorf("expected an import to read the connection, got %s", imported.Connection)
+	}
+	if !testSnowflakeAttribute(imported, "password").IsNull() {
+		t.Error("expected an imported data source to have no password")
+	}
+}
+
+func TestDataSource_validateConnection(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	snowflake := map[string]attr.Value{
+		"hostname":  types.StringValue("example.snowflakecomputing.com"),
+		"database":  types.StringValue(testDataSourceDatabase),
+		"warehouse": types.StringValue("tf_acc_warehouse"),
+		"username":  types.StringValue("tf_acc_user"),
+		"password":  types.StringValue("tf_acc_password"),
+	}
+	with := func(values map[string]attr.Value, changes map[string]attr.Value) map[string]attr.Value {
+		merged := map[string]attr.Value{}
+		for name, value := range values {
+			merged[name] = value
+		}
+		for name, value := range changes {
+			merged[name] = value
+		}
+		return merged
+	}
+
+	tests := map[string]struct {
+		connection func(t *testing.T) types.Object
+		error      string
+	}{
+		"password": {
+			connection: func(t *testing.T) types.Object { return testConnectionDetails(t, "snowflake", snowflake) },
+		},
+		"password from a file": {
+			connection: func(t *testing.T) types.Object {
+				return testConnectionDetails(t, "snowflake", with(snowflake, map[string]attr.Value{
+					"password":         types.StringNull(),
+					"password_file":    types.StringValue("password.txt"),
+					"password_version": types.Int64Value(2),
+				}))
+			},
+		},
+		"password and password file": {
+			connection: func(t *testing.T) types.Object {
+				return testConnectionDetails(t, "snowflake", with(snowflake, map[string]attr.Value{
+					"password_file": types.StringValue("password.txt"),
+				}))
+			},
+			error: "Conflicting connection attributes",
+		},
+		"no handler": {
+			connection: func(t *testing.T) types.Object { return testConnectionDetails(t, "", nil) },
+			error:      "Missing connection handler",
+		},
+		"two handlers": {
+			connection: func(t *testing.T) types.Object {
+				connection := testConnectionDetails(t, "snowflake", snowflake).Attributes()
+				connection["postgresql"] = testConnectionDetails(t, "postgresql", map[string]attr.Value{
+					"hostname": types.StringValue("db.example.com"),
+					"database": types.StringValue("analytics"),
+				}).Attributes()["postgresql"]
+				return types.ObjectValueMust(connectionDetailsAttributeTypes(), connection)
+			},
+			error: "Conflicting connection handlers",
+		},
+		"missing credential": {
+			connection: func(t *testing.T) types.Object {
+				return testConnectionDetails(t, "snowflake", with(snowflake, map[string]attr.Value{
+					"password": types.StringNull(),
+				}))
+			},
+			error: "Missing connection attribute",
+		},
+		"unsupported method": {
+			connection: func(t *testing.T) types.Object {
+				return testConnectionDetails(t, "postgresql", map[string]attr.Value{
+					"hostname":              types.StringValue("db.example.com"),
+					"database":              types.StringValue("analytics"),
+					"authentication_method": types.StringValue("key_pair"),
+				})
+			},
+			error: "Invalid value",
+		},
+		"hostname with a scheme": {
+			connection: func(t *testing.T) types.Object {
+				return testConnectionDetails(t, "snowflake", with(snowflake, map[string]attr.Value{
+					"hostname": types.StringValue("https://example.snowflakecomputing.com"),
+				}))
+			},
+			error: "Invalid hostname",
+		},
+		"port out of range": {
+			connection: func(t *testing.T) types.Object {
+				return testConnectionDetails(t, "snowflake", with(snowflake, map[string]attr.Value{
+					"port": types.Int64Value(70000),
+				}))
+			},
+			error: "Invalid value",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			config := testDataSourceModel(t, nil)
+			config.Id = types.StringNull()
+			config.NamePreview = types.ObjectNull(namePreviewAttributes())
+			config.Connection = test.connection(t)
+
+			diags := l.validateErrors(config)
+			if test.error == "" {
+				if len(diags) != 0 {
+					t.Fatalf("expected the configuration to be valid, got %s: %s", diags[0].Summary, diags[0].Detail)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Summary != test.error {
+				for _, d := range diags {
+					t.Logf("%s: %s", d.Summary, d.Detail)
+				}
+				t.Fatalf("expected a single %q error, got %d diagnostics", test.error, len(diags))
+			}
+		})
+	}
+}
+
+func TestDataSource_inlinePasswordIsDeprecated(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	config := testDataSourceModel(t, nil)
+	config.Id = types.StringNull()
+	config.NamePreview = types.ObjectNull(namePreviewAttributes())
+
+	diags := l.validate(config)
+	if len(diags) != 1 || diags[0].Severity != tfprotov6.DiagnosticSeverityWarning || diags[0].Summary != "Attribute Deprecated" {
+		t.Fatalf("expected the inline password to be deprecated, got %d diagnostics", len(diags))
+	}
+
+	config.Connection = testConnectionDetails(t, "snowflake", map[string]attr.Value{
+		"hostname":      types.StringValue("example.snowflakecomputing.com"),
+		"database":      types.StringValue(testDataSourceDatabase),
+		"warehouse":     types.StringValue("tf_acc_warehouse"),
+		"username":      types.StringValue("tf_acc_user"),
+		"password_file": types.StringValue("password.txt"),
+	})
+	if diags := l.validate(config); len(diags) != 0 {
+		t.Errorf("expected password_file not to warn, got %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+}
+
+func TestDataSource_planConnectionDefaults(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+
+	config := testDataSourceModel(t, nil)
+	config.Id = types.StringNull()
+	config.NamePreview = types.ObjectNull(namePreviewAttributes())
+	config.Connection = testConnectionDetails(t, "redshift", map[string]attr.Value{
+		"hostname": types.StringValue("cluster.redshift.amazonaws.com"),
+		"database": types.StringValue("dev"),
+		"username": types.StringValue("awsuser"),
+		"password": types.StringValue("secret"),
+	})
+
+	planned := l.planCreate(config)
+	redshift := planned.Connection.Attributes()["redshift"].(types.Object).Attributes()
+	if port := redshift["port"].(types.Int64); port.ValueInt64() != 5439 {
+		t.Errorf("expected the handler's default port, got %s", port)
+	}
+	if method := redshift["authentication_method"].(types.String); method.ValueString() != authenticationPassword {
+		t.Errorf("expected the handler's default authentication method, got %s", method)
+	}
+	if !planned.Connection.Attributes()["snowflake"].IsNull() {
+		t.Error("expected the other handlers to stay null")
+	}
+
+	connection, diags := connectionFromResourceData(planned.Connection)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := client.DataSourceConnection{
+		Handler:  "Redshift",
+		Hostname: "cluster.redshift.amazonaws.com",
+		Port:     5439,
+		Database: "dev",
+		Username: "awsuser",
+		Password: "secret",
+	}
+	if !reflect.DeepEqual(connection, expected) {
+		t.Errorf("expected %+v to be sent to Immuta, got %+v", expected, connection)
+	}
+}
+
+func TestDataSource_passwordFile(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	passwordPath := filepath.Join(t.TempDir(), "password.txt")
+	if err := os.WriteFile(passwordPath, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	passwordConfig := func(username string, version int64) *DataSourceResourceModel {
+		config := testDataSourceModel(t, nil)
+		config.Id = types.StringNull()
+		config.NamePreview = types.ObjectNull(namePreviewAttributes())
+		config.Connection = testConnectionDetails(t, "snowflake", map[string]attr.Value{
+			"hostname":         types.StringValue("example.snowflakecomputing.com"),
+			"database":         types.StringValue(testDataSourceDatabase),
+			"warehouse":        types.StringValue("tf_acc_warehouse"),
+			"username":         types.StringValue(username),
+			"password_file":    types.StringValue(passwordPath),
+			"password_version": types.Int64Value(version),
+		})
+		return config
+	}
+	sentPassword := func() (password string) {
+		l.server.Mutate(func(state *fakeimmuta.State) {
+			password = state.DataSources[testDataSourceConnectionKey].Connection.Password
+		})
+		return password
+	}
+
+	planned, private := l.planChange(nil, nil, passwordConfig("tf_acc_user", 1))
+	if strings.Contains(planned.Connection.String(), "s3cret") {
+		t.Fatalf("expected nothing of the password to be planned, got %s", planned.Connection)
+	}
+	digest := testSnowflakeAttribute(planned, "password_hmac")
+	if digest.ValueString() == "" || digest.ValueString() == fmt.Sprintf("%x", sha256.Sum256([]byte("s3cret"))) {
+		t.Fatalf("expected a salted HMAC of the password to be planned, got %s", digest)
+	}
+	if other := l.planCreate(passwordConfig("tf_acc_user", 1)); testSnowflakeAttribute(other, "password_hmac") == digest {
+		t.Error("expected another resource to get another salt")
+	}
+
+	planned.Id = types.StringUnknown()
+	created := l.create(planned)
+	if !testSnowflakeAttribute(created, "password").IsNull() || sentPassword() != "s3cret" {
+		t.Errorf("expected the password to be sent to Immuta but not stored, got %s", created.Connection)
+	}
+	read := l.read(created)
+	if !read.Connection.Equal(created.Connection) {
+		t.Errorf("expected a refresh to keep the password file, version and HMAC, got %s", read.Connection)
+	}
+	if unchanged, _ := l.planChange(created, private, passwordConfig("tf_acc_user", 1)); testSnowflakeAttribute(unchanged, "password_hmac") != digest {
+		t.Errorf("expected the same password to plan the same HMAC, got %s", testSnowflakeAttribute(unchanged, "password_hmac"))
+	}
+
+	if err := os.WriteFile(passwordPath, []byte("rotated"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rotated, private := l.planChange(created, private, passwordConfig("tf_acc_user", 1))
+	if testSnowflakeAttribute(rotated, "password_hmac") == digest {
+		t.Fatal("expected a rewritten file to change the HMAC")
+	}
+	rotated.Id = created.Id
+	updated := l.update(created, rotated)
+	if sentPassword() != "rotated" {
+		t.Errorf("expected the rewritten file to be sent to Immuta, got %q", sentPassword())
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.DataSources[testDataSourceConnectionKey].Connection.Password = ""
+	})
+	bumped, _ := l.planChange(updated, private, passwordConfig("tf_acc_user", 2))
+	bumped.Id = created.Id
+	l.update(updated, bumped)
+	if sentPassword() != "rotated" {
+		t.Errorf("expected bumping password_version to send the password again, got %q", sentPassword())
+	}
+
+	if err := os.Remove(passwordPath); err != nil {
+		t.Fatal(err)
+	}
+	diags := l.modifyPlan(nil, bumped)
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Invalid connection attribute" {
+		t.Errorf("expected a missing password file to fail the plan, got %v", diags)
+	}
+}
+
+func TestDataSource_upgradeFlatConnection(t *testing.T) {
+	r := NewDataSourceResource().(*DataSourceResource)
+	upgrader := r.UpgradeState(context.Background())[0]
+
+	prior := testDataSourceModel(t, nil)
+	prior.Id = types.StringValue(testDataSourceConnectionKey)
+	prior.NamePreview = types.ObjectNull(namePreviewAttributes())
+	flat, diags := types.ObjectValueFrom(context.Background(), dataSourceConnectionAttributesV0(), client.DataSourceConnection{
+		Handler:   "Snowflake",
+		Hostname:  "example.snowflakecomputing.com",
+		Port:      443,
+		Database:  testDataSourceDatabase,
+		Username:  "tf_acc_user",
+		Password:  "secret",
+		Warehouse: "tf_acc_warehouse",
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	prior.Connection = flat
+
+	state := tfsdk.State{Schema: *upgrader.PriorSchema}
+	state.Raw = tftypes.NewValue(upgrader.PriorSchema.Type().TerraformType(context.Background()), nil)
+	if diags := state.Set(context.Background(), prior); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	current := fwresource.SchemaResponse{}
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, &current)
+	resp := fwresource.UpgradeStateResponse{State: tfsdk.State{Schema: current.Schema}}
+	upgrader.StateUpgrader(context.Background(), fwresource.UpgradeStateRequest{State: &state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	upgraded := &DataSourceResourceModel{}
+	if diags := resp.State.Get(context.Background(), upgraded); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if testSnowflakeAttribute(upgraded, "authentication_method").ValueString() != authenticationPassword {
+		t.Errorf("expected the default authentication method, got %s", upgraded.Connection)
+	}
+	if testSnowflakeAttribute(upgraded, "password").ValueString() != "secret" || testSnowflakeAttribute(upgraded, "warehouse").ValueString() != "tf_acc_warehouse" {
+		t.Errorf("expected the flat attributes to be moved under the handler, got %s", upgraded.Connection)
+	}
+	if !testSnowflakeAttribute(upgraded, "schema").IsNull() || !testSnowflakeAttribute(upgraded, "password_file").IsNull() {
+		t.Errorf("expected attributes that were not set to stay null, got %s", upgraded.Connection)
+	}
+	if upgraded.ConnectionKey.ValueString() != testDataSourceConnectionKey {
+		t.Errorf("expected the other attributes to be kept, got %s", upgraded.ConnectionKey)
+	}
+}
+
+func TestDataSource_validateNameTemplate(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	valid := client.DataSourceNameTemplate{
+		DataSourceFormat:        "<Database> <Schema> <Tablename>",
+		TableFormat:             "<database>_<schema>_<tablename>",
+		SchemaFormat:            "<DATABASE>_<SCHEMA>",
+		SchemaProjectNameFormat: "<database>.<schema>",
+	}
+
+	tests := map[string]struct {
+		change func(nameTemplate *client.DataSourceNameTemplate)
+		error  string
+	}{
+		"case variants": {
+			change: func(*client.DataSourceNameTemplate) {},
+		},
+		"typo": {
+			change: func(nameTemplate *client.DataSourceNameTemplate) {
+				nameTemplate.TableFormat = "<database>_<TABELNAME>_<tablename>"
+			},
+			error: "Invalid name template",
+		},
+		"mixed case": {
+			change: func(nameTemplate *client.DataSourceNameTemplate) { nameTemplate.DataSourceFormat = "<TableName>" },
+			error:  "Invalid name template",
+		},
+		"unclosed placeholder": {
+			change: func(nameTemplate *client.DataSourceNameTemplate) { nameTemplate.SchemaFormat = "<SCHEMA" },
+			error:  "Invalid name template",
+		},
+		"missing table name": {
+			change: func(nameTemplate *client.DataSourceNameTemplate) {
+				nameTemplate.DataSourceFormat = "<DATABASE>.<SCHEMA>"
+			},
+			error: "Invalid name template",
+		},
+		"table name in the schema format": {
+			change: func(nameTemplate *client.DataSourceNameTemplate) {
+				nameTemplate.SchemaProjectNameFormat = "<schema>.<tablename>"
+			},
+			error: "Invalid name template",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			nameTemplate := valid
+			test.change(&nameTemplate)
+			config := testDataSourceModel(t, nil)
+			config.Id = types.StringNull()
+			config.NamePreview = types.ObjectNull(namePreviewAttributes())
+			config.NameTemplate, _ = types.ObjectValueFrom(context.Background(), config.NameTemplateAttributes(), nameTemplate)
+
+			diags := l.validateErrors(config)
+			if test.error == "" {
+				if len(diags) != 0 {
+					t.Fatalf("expected the configuration to be valid, got %s: %s", diags[0].Summary, diags[0].Detail)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Summary != test.error {
+				for _, d := range diags {
+					t.Logf("%s: %s", d.Summary, d.Detail)
+				}
+				t.Fatalf("expected a single %q error, got %d diagnostics", test.error, len(diags))
+			}
+		})
+	}
+}
+
+func TestDataSource_planNamePreview(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	config := testDataSourceModel(t, nil)
+	config.Id = types.StringNull()
+	config.NamePreview = types.ObjectNull(namePreviewAttributes())
+
+	planned := l.planCreate(config)
+	expected := map[string]string{
+		"data_source":    "tfacc::TERRAFORM_INTEGRATION_TEST.TEST_SCHEMA.SAMPLE_TABLE",
+		"table":          "tfacc_terraform_integration_test_test_schema_sample_table",
+		"schema":         "tfacc_terraform_integration_test_test_schema",
+		"schema_project": "tfacc::terraform_integration_test.test_schema",
+	}
+	for name, value := range expected {
+		if rendered := planned.NamePreview.Attributes()[name].(types.String); rendered.ValueString() != value {
+			t.Errorf("expected %s to be rendered as %q, got %s", name, value, rendered)
+		}
+	}
+
+	config.NameTemplate, _ = types.ObjectValueFrom(context.Background(), config.NameTemplateAttributes(), client.DataSourceNameTemplate{
+		DataSourceFormat:        "<Schema> <Tablename>",
+		TableFormat:             "<tablename>",
+		SchemaFormat:            "<schema>",
+		SchemaProjectNameFormat: "<Schema>",
+	})
+	config.Sources = testSources(t, map[string]attr.Value{"schema": types.StringValue("SALES"), "table": types.StringValue("CUSTOMER_ORDERS")})
+	planned = l.planCreate(config)
+	if rendered := planned.NamePreview.Attributes()["data_source"].(types.String); rendered.ValueString() != "Sales Customer_orders" {
+		t.Errorf("expected the first table of sources to be rendered in the case of the placeholders, got %s", rendered)
+	}
+
+	config.NameTemplate = types.ObjectNull(config.NameTemplateAttributes())
+	if planned := l.planCreate(config); !planned.NamePreview.IsNull() {
+		t.Errorf("expected no preview without a name template, got %s", planned.NamePreview)
+	}
+
+	created := l.create(testDataSourceModel(t, nil))
+	if created.NamePreview.IsUnknown() || !l.read(created).NamePreview.Equal(created.NamePreview) {
+		t.Errorf("expected the preview to be kept in the state, got %s", created.NamePreview)
+	}
+}
+
+func TestDataSource_createTimeout(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+
+	planned := testDataSourceModel(t, nil)
+	planned.Timeouts = testTimeouts(operationCreate, "1ns")
+
+	diags := l.tryCreate(planned)
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Timed out waiting for Immuta" {
+		t.Fatalf("expected the create to time out, got %v", diags)
+	}
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if _, ok := state.DataSources[testDataSourceConnectionKey]; ok {
+			t.Error("expected a timed out create not to reach Immuta")
+		}
+	})
+}
+
+func TestDataSource_waitForCompletion(t *testing.T) {
+	previous := dataSourcePollInterval
+	dataSourcePollInterval = time.Millisecond
+	t.Cleanup(func() { dataSourcePollInterval = previous })
+
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.DetectionPolls[testDataSourceConnectionKey] = 3
+	})
+
+	created := l.create(testDataSourceModel(t, nil))
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if state.DetectionPolls[testDataSourceConnectionKey] != 3 {
+			t.Error("expected the detection not to be checked without waiting")
+		}
+	})
+
+	planned := testDataSourceModel(t, nil)
+	planned.Id = created.Id
+	planned.WaitForCompletion = types.BoolValue(true)
+	l.update(created, planned)
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if state.DetectionPolls[testDataSourceConnectionKey] != 0 {
+			t.Errorf("expected to check until the detection completed, %d checks left", state.DetectionPolls[testDataSourceConnectionKey])
+		}
+		delete(state.DataSources, testDataSourceConnectionKey)
+		state.DetectionPolls[testDataSourceConnectionKey] = 1000
+	})
+
+	planned = testDataSourceModel(t, nil)
+	planned.WaitForCompletion = types.BoolValue(true)
+	planned.Timeouts = testTimeouts(operationCreate, "50ms")
+	kept, diags := l.tryCreateState(planned)
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Timed out waiting for Immuta" {
+		t.Fatalf("expected waiting to be bounded by the create timeout, got %v", diags)
+	}
+	if kept == nil || kept.Id.ValueString() != testDataSourceConnectionKey {
+		t.Fatalf("expected the registered data source to be kept in the state, got %+v", kept)
+	}
+
+	planned = testDataSourceModel(t, nil)
+	planned.Id = kept.Id
+	planned.WaitForCompletion = types.BoolValue(true)
+	planned.Timeouts = testTimeouts(operationUpdate, "50ms")
+	kept, diags = l.tryUpdate(kept, planned)
+	if !diags.HasError() || kept.Id.ValueString() != testDataSourceConnectionKey {
+		t.Errorf("expected a timed out update to keep the data source in the state, got %v and %+v", diags, kept)
+	}
+}
+
+func TestDataSource_waitForDetectionFailsOnChangedRegistration(t *testing.T) {
+	previous := dataSourcePollInterval
+	dataSourcePollInterval = time.Millisecond
+	t.Cleanup(func() { dataSourcePollInterval = previous })
+
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	l.create(testDataSourceModel(t, nil))
+
+	input := client.DataSourceInput{}
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		input = *state.DataSources[testDataSourceConnectionKey]
+		state.DetectionPolls[testDataSourceConnectionKey] = 1000
+		state.DataSources[testDataSourceConnectionKey].Connection.Warehouse = "edited_warehouse"
+	})
+
+	err := waitForDetection(context.Background(), l.server.Client().DataSources(), input)
+	if err == nil || !strings.Contains(err.Error(), "changed while waiting") {
+		t.Errorf("expected waiting to fail once the registration no longer matches, got %v", err)
+	}
+}
+
+func TestDataSource_planPreview(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+
+	warnings := l.modifyPlan(nil, testDataSourceModel(t, nil))
+	if warnings.WarningsCount() != 1 || warnings.Warnings()[0].Summary() != "Immuta will create 1 data source(s)" {
+		t.Fatalf("expected the create to be previewed, got %v", warnings)
+	}
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if len(state.DataSources) != 0 {
+			t.Error("expected the preview not to register anything")
+		}
+	})
+
+	created := l.create(testDataSourceModel(t, nil))
+	if warnings := l.modifyPlan(created, created); len(warnings) != 0 {
+		t.Errorf("expected an unchanged plan not to be previewed, got %v", warnings)
+	}
+
+	planned := testDataSourceModel(t, []string{"a"})
+	planned.Id = created.Id
+	warnings = l.modifyPlan(created, planned)
+	if warnings.WarningsCount() != 1 || !strings.Contains(warnings.Warnings()[0].Detail(), testDataSourceConnectionKey) {
+		t.Errorf("expected the update to be previewed, got %v", warnings)
+	}
+
+	warnings = l.modifyPlan(created, nil)
+	if warnings.WarningsCount() != 1 || warnings.Warnings()[0].Summary() != "Immuta will delete 1 data source(s)" {
+		t.Errorf("expected the destroy to be previewed, got %v", warnings)
+	}
+
+	unknown := testDataSourceModel(t, nil)
+	unknown.Connection = types.ObjectUnknown(unknown.ConnectionAttributes())
+	if warnings := l.modifyPlan(nil, unknown); len(warnings) != 0 {
+		t.Errorf("expected a plan with unknown values not to be previewed, got %v", warnings)
+	}
+}
+
+func TestDataSource_planSources(t *testing.T) {
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.Catalog = []string{
+			testDataSourceSchema + ".CUSTOMERS",
+			testDataSourceSchema + ".ORDERS",
+			testDataSourceSchema + ".ORDERS_TMP",
+			"OTHER_SCHEMA.ORDERS",
+		}
+	})
+
+	planned := testDataSourceModel(t, nil)
+	planned.Sources = testSources(t,
+		map[string]attr.Value{"table": types.StringValue("CUSTOMERS")},
+		map[string]attr.Value{"schema": types.StringValue("OTHER_SCHEMA"), "table": types.StringValue("ORDERS")},
+	)
+	diags := l.modifyPlan(nil, planned)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected the selected tables to be previewed, got %v", diags)
+	}
+	for _, table := range []string{testDataSourceSchema + ".CUSTOMERS", "OTHER_SCHEMA.ORDERS"} {
+		if !strings.Contains(diags.Warnings()[0].Detail(), table) {
+			t.Errorf("expected %s to be selected, got %s", table, diags.Warnings()[0].Detail())
+		}
+	}
+	if strings.Contains(diags.Warnings()[0].Detail(), "ORDERS_TMP") || strings.Contains(diags.Warnings()[0].Detail(), testDataSourceSchema+".ORDERS") {
+		t.Errorf("expected the tables that were not selected to be left out, got %s", diags.Warnings()[0].Detail())
+	}
+
+	missing := testDataSourceModel(t, nil)
+	missing.Sources = testSources(t, map[string]attr.Value{"table": types.StringValue("MISSING")})
+	diags = l.modifyPlan(nil, missing)
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Immuta rejected the data source" {
+		t.Errorf("expected a missing table to fail the plan, got %v", diags)
+	}
+
+	created := l.create(planned)
+	if !reflect.DeepEqual(created.Sources, planned.Sources) {
+		t.Errorf("expected the sources to be kept, got %s", created.Sources)
+	}
+	if read := l.read(created); !read.Sources.Equal(planned.Sources) {
+		t.Errorf("expected the sources to be read back unchanged, got %s", read.Sources)
+	}
+}
+
+func TestDataSource_traces(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	l := newLifecycle[DataSourceResourceModel](t, NewDataSourceResource())
+	l.create(testDataSourceModel(t, nil))
+
+	spans := exporter.GetSpans()
+	operation := spans[len(spans)-1]
+	if operation.Name != "immuta_data_source.create" || operation.Status.Code != codes.Unset {
+		t.Fatalf("expected the create to end with a successful operation span, got %s %v", operation.Name, operation.Status)
+	}
+	requests := spans[:len(spans)-1]
+	if len(requests) == 0 {
+		t.Fatal("expected a span per request")
+	}
+	for _, request := range requests {
+		if request.Parent.SpanID() != operation.SpanContext.SpanID() {
+			t.Errorf("expected %s to be a child of the operation span", request.Name)
+		}
+		if !hasAttribute(request.Attributes, semconv.HTTPStatusCodeKey.Int(http.StatusOK)) {
+			t.Errorf("expected %s to carry the status code, got %v", request.Name, request.Attributes)
+		}
+	}
+
+	exporter.Reset()
+	planned := testDataSourceModel(t, nil)
+	planned.Timeouts = testTimeouts(operationCreate, "1ns")
+	l.tryCreate(planned)
+
+	spans = exporter.GetSpans()
+	for _, span := range spans {
+		if span.Status.Code != codes.Error || len(span.Events) == 0 {
+			t.Errorf("expected %s to record the timeout, got %v", span.Name, span.Status)
+		}
+	}
+	if len(spans) != 2 {
+		t.Errorf("expected the failed request and the operation to be traced, got %d spans", len(spans))
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, expected attribute.KeyValue) bool {
+	for _, kv := range attributes {
+		if kv == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func testDataSourceModel(t *testing.T, tags []string) *DataSourceResourceModel {
+	model := &DataSourceResourceModel{}
+	object := func(attributeTypes map[string]attr.Type, value interface{}) types.Object {
+		object, diags := types.ObjectValueFrom(context.Background(), attributeTypes, value)
+		if diags.HasError() {
+			t.Fatalf("could not build the model: %v", diags)
+		}
+		return object
+	}
+
+	model.Id = types.StringUnknown()
+	model.ConnectionKey = types.StringValue(testDataSourceConnectionKey)
+	model.NameTemplate = object(model.NameTemplateAttributes(), client.DataSourceNameTemplate{
+		DataSourceFormat:        "tfacc::<DATABASE>.<SCHEMA>.<TABLENAME>",
+		TableFormat:             "tfacc_<database>_<schema>_<tablename>",
+		SchemaFormat:            "tfacc_<database>_<schema>",
+		SchemaProjectNameFormat: "tfacc::<database>.<schema>",
+	})
+	model.Options = object(model.OptionsAttributes(), client.DataSourceOptions{TableTags: tags})
+	model.Owners = types.ListNull(types.ObjectType{AttrTypes: model.OwnersAttributes()})
+	model.Sources = types.ListNull(types.ObjectType{AttrTypes: model.SourcesAttributes()})
+	model.Connection = testConnectionDetails(t, "snowflake", map[string]attr.Value{
+		"hostname":              types.StringValue("example.snowflakecomputing.com"),
+		"port":                  types.Int64Value(443),
+		"database":              types.StringValue(testDataSourceDatabase),
+		"schema":                types.StringValue(testDataSourceSchema),
+		"authentication_method": types.StringValue(authenticationPassword),
+		"username":              types.StringValue("tf_acc_user"),
+		"password":              types.StringValue("tf_acc_password"),
+		"warehouse":             types.StringValue("tf_acc_warehouse"),
+	})
+	model.WaitForCompletion = types.BoolNull()
+	model.NamePreview = types.ObjectUnknown(namePreviewAttributes())
+	model.Timeouts = noTimeouts()
+
+	return model
+}
+
+// testConnectionDetails sets one handler of connection_details, its attributes left out are null
+func testConnectionDetails(t *testing.T, handler string, values map[string]attr.Value) types.Object {
+	handlers := map[string]attr.Value{}
+	for name, handlerType := range connectionDetailsAttributeTypes() {
+		attributeTypes := handlerType.(types.ObjectType).AttrTypes
+		if name != handler {
+			handlers[name] = types.ObjectNull(attributeTypes)
+			continue
+		}
+
+		attributes := map[string]attr.Value{}
+		for attributeName, attributeType := range attributeTypes {
+			attributes[attributeName] = nullValue(context.Background(), attributeType)
+		}
+		for attributeName, value := range values {
+			if _, ok := attributes[attributeName]; !ok {
+				t.Fatalf("%s has no attribute %s", handler, attributeName)
+			}
+			attributes[attributeName] = value
+		}
+		handlers[name] = types.ObjectValueMust(attributeTypes, attributes)
+	}
+	return types.ObjectValueMust(connectionDetailsAttributeTypes(), handlers)
+}
+
+func testSnowflakeAttribute(model *DataSourceResourceModel, name string) types.String {
+	snowflake := model.Connection.Attributes()["snowflake"].(types.Object)
+	value, _ := snowflake.Attributes()[name].(types.String)
+	return value
+}
+
+// testSources builds the sources attribute, the attributes left out of each source are null
+func testSources(t *testing.T, sources ...map[string]attr.Value) types.List {
+	attributeTypes := (&DataSourceResourceModel{}).SourcesAttributes()
+	elements := []attr.Value{}
+	for _, values := range sources {
+		attributes := map[string]attr.Value{}
+		for name, attributeType := range attributeTypes {
+			attributes[name] = nullValue(context.Background(), attributeType)
+		}
+		for name, value := range values {
+			if _, ok := attributes[name]; !ok {
+				t.Fatalf("sources have no attribute %s", name)
+			}
+			attributes[name] = value
+		}
+		elements = append(elements, types.ObjectValueMust(attributeTypes, attributes))
+	}
+	return types.ListValueMust(types.ObjectType{AttrTypes: attributeTypes}, elements)
+}
@@ -3,6 +3,7 @@ package immuta
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -10,8 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/immuta/terraform-provider-immuta/client"
 	"strconv"
-	"strings"
-	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -24,27 +23,29 @@ func NewProjectResource() resource.Resource {
 
 // ProjectResource defines the resource implementation.
 type ProjectResource struct {
-	client *client.ImmutaClient
+	projects client.ProjectsService
+	features client.FeatureChecker
 }
 
 // ProjectResourceModel describes the resource data model.
 type ProjectResourceModel struct {
-	Id                 types.String `tfsdk:"id"`
-	Name               types.String `tfsdk:"name"`
-	Description        types.String `tfsdk:"description"`
-	ProjectKey         types.String `tfsdk:"project_key"`
-	Documentation      types.String `tfsdk:"documentation"`
-	AllowMaskedJoins   types.Bool   `tfsdk:"allow_masked_joins"`
-	SubscriptionPolicy types.Map    `tfsdk:"subscription_policy"`
-	Tags               types.List   `tfsdk:"tags"`
-	Purposes           types.List   `tfsdk:"purposes"`
+	Id                 types.String   `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	Description        types.String   `tfsdk:"description"`
+	ProjectKey         types.String   `tfsdk:"project_key"`
+	Documentation      types.String   `tfsdk:"documentation"`
+	AllowMaskedJoins   types.Bool     `tfsdk:"allow_masked_joins"`
+	SubscriptionPolicy types.Map      `tfsdk:"subscription_policy"`
+	Tags               types.List     `tfsdk:"tags"`
+	Purposes           types.List     `tfsdk:"purposes"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ProjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_project"
 }
 
-func (r *ProjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ProjectResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Immuta project.",
@@ -88,6 +89,9 @@ func (r *ProjectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				ElementType:         types.StringType,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -97,21 +101,25 @@ func (r *ProjectResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	immutaClient, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *immutaClient.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = immutaClient
+	r.projects = immutaClient.Projects()
+	r.features = immutaClient
 }
 
 func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_project", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *ProjectResourceModel
 
 	// Read Terraform plan data into the model
@@ -121,6 +129,13 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationCreate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	subscriptionPolicy := make(map[string]interface{})
 	if diags := data.SubscriptionPolicy.ElementsAs(ctx, &subscriptionPolicy, false); diags != nil {
 		resp.Diagnostics.Append(diags...)
@@ -143,7 +158,7 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		data.Documentation = types.StringValue("# " + data.Name.ValueString())
 	}
 
-	project := ProjectInput{
+	project := client.ProjectInput{
 		Name:               data.Name.ValueString(),
 		ProjectKey:         data.ProjectKey.ValueString(),
 		Description:        data.Description.ValueString(),
@@ -154,14 +169,15 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		Purposes:           purposes,
 	}
 
-	projectResponse, err := r.UpsertProject(project)
+	resp.Diagnostics.Append(featureWarning(r.features, client.FeatureV2Project)...)
+	projectResponse, err := r.projects.Upsert(ctx, project)
 	if err != nil {
 
 		tflog.Warn(ctx, "Trying to acknowledge purposes for the project")
 
 		// try acknowledging to get around the "You must first acknowledge" error/bug
-		if strings.Contains(err.Error(), "You must first acknowledge") {
-			projectState, readErr := r.FindProject(data.Name.ValueString())
+		if client.IsAcknowledgementRequired(err) {
+			projectState, readErr := r.projects.Find(ctx, data.Name.ValueString())
 			if readErr != nil {
 				tflog.Error(ctx, "Error getting project to acknowledge")
 				resp.Diagnostics.AddError(
@@ -170,7 +186,7 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 				)
 				return
 			}
-			projectState, readErr = r.GetProject(strconv.Itoa(projectState.Id))
+			projectState, readErr = r.projects.Get(ctx, strconv.Itoa(projectState.Id))
 			if readErr != nil {
 				tflog.Error(ctx, "Error getting project to acknowledge")
 				resp.Diagnostics.AddError(
@@ -180,7 +196,7 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 				return
 			}
 
-			if ackError := r.AcknowledgeProject(projectState.Id, projectState.SubscriptionId); ackError != nil {
+			if ackError := r.projects.Acknowledge(ctx, projectState.Id, projectState.SubscriptionId); ackError != nil {
 				tflog.Error(ctx, "Error acknowledging")
 				resp.Diagnostics.AddError(
 					"Error acknowledging project",
@@ -189,18 +205,18 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 			}
 		}
 
-		projectResponse, err = r.UpsertProject(project)
+		projectResponse, err = r.projects.Upsert(ctx, project)
 
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Error creating project",
+				clientErrorSummary("Error creating project", err),
 				fmt.Sprintf("Error creating project: %s", err),
 			)
 			return
 		}
 	}
 
-	projectState, err := r.GetProject(strconv.Itoa(projectResponse.ProjectId))
+	projectState, err := r.projects.Get(ctx, strconv.Itoa(projectResponse.ProjectId))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading project",
@@ -208,7 +224,7 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		)
 		return
 	}
-	if err := r.AcknowledgeProject(projectState.Id, projectState.SubscriptionId); err != nil {
+	if err := r.projects.Acknowledge(ctx, projectState.Id, projectState.SubscriptionId); err != nil {
 		resp.Diagnostics.AddError(
 			"Error acknowledging project",
 			fmt.Sprintf("Error acknowledging project: %s", err),
@@ -223,6 +239,9 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_project", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *ProjectResourceModel
 
 	// Read Terraform prior state data into the model
@@ -232,7 +251,19 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	project, err := r.GetProject(data.Id.ValueString())
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationRead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	project, err := r.projects.Get(ctx, data.Id.ValueString())
+	if client.IsNotFound(err) {
+		// Project no longer exists, remove from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading project",
@@ -241,8 +272,10 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	// todo check if id or project key have changed
-
+	// the project key is not known after an import until it has been read
+	if data.ProjectKey.ValueString() != project.ProjectKey {
+		data.ProjectKey = types.StringValue(project.ProjectKey)
+	}
 	if data.Name.ValueString() != project.Name {
 		data.Name = types.StringValue(project.Name)
 	}
@@ -291,6 +324,9 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_project", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *ProjectResourceModel
 
 	// Read Terraform plan data into the model
@@ -300,6 +336,13 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationUpdate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	// todo
 	// Actually update the Project
 
@@ -325,7 +368,7 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 		data.Documentation = types.StringValue("# " + data.Name.ValueString())
 	}
 
-	project := ProjectInput{
+	project := client.ProjectInput{
 		Name:               data.Name.ValueString(),
 		ProjectKey:         data.ProjectKey.ValueString(),
 		Description:        data.Description.ValueString(),
@@ -336,15 +379,16 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 		Purposes:           purposes,
 	}
 
-	projectResponse, err := r.UpsertProject(project)
+	resp.Diagnostics.Append(featureWarning(r.features, client.FeatureV2Project)...)
+	projectResponse, err := r.projects.Upsert(ctx, project)
 
 	if err != nil {
 
 		tflog.Warn(ctx, "Trying to acknowledge purposes for the project")
 
 		// try acknowledging to get around the "You must first acknowledge" error/bug
-		if strings.Contains(err.Error(), "You must first acknowledge") {
-			projectState, readErr := r.FindProject(data.Name.ValueString())
+		if client.IsAcknowledgementRequired(err) {
+			projectState, readErr := r.projects.Find(ctx, data.Name.ValueString())
 			if readErr != nil {
 				tflog.Error(ctx, "Error getting project to acknowledge")
 				resp.Diagnostics.AddError(
@@ -353,7 +397,7 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 				)
 				return
 			}
-			projectState, readErr = r.GetProject(strconv.Itoa(projectState.Id))
+			projectState, readErr = r.projects.Get(ctx, strconv.Itoa(projectState.Id))
 			if readErr != nil {
 				tflog.Error(ctx, "Error getting project to acknowledge")
 				resp.Diagnostics.AddError(
@@ -363,7 +407,7 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 				return
 			}
 
-			if ackError := r.AcknowledgeProject(projectState.Id, projectState.SubscriptionId); ackError != nil {
+			if ackError := r.projects.Acknowledge(ctx, projectState.Id, projectState.SubscriptionId); ackError != nil {
 				tflog.Error(ctx, "Error acknowledging project")
 				resp.Diagnostics.AddError(
 					"Error acknowledging project",
@@ -372,18 +416,18 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 			}
 		}
 
-		projectResponse, err = r.UpsertProject(project)
+		projectResponse, err = r.projects.Upsert(ctx, project)
 
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Error creating project",
+				clientErrorSummary("Error creating project", err),
 				fmt.Sprintf("Error creating project: %s", err),
 			)
 			return
 		}
 	}
 
-	projectState, err := r.GetProject(strconv.Itoa(projectResponse.ProjectId))
+	projectState, err := r.projects.Get(ctx, strconv.Itoa(projectResponse.ProjectId))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading project",
@@ -391,7 +435,7 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 		)
 		return
 	}
-	if err := r.AcknowledgeProject(projectState.Id, projectState.SubscriptionId); err != nil {
+	if err := r.projects.Acknowledge(ctx, projectState.Id, projectState.SubscriptionId); err != nil {
 		resp.Diagnostics.AddError(
 			"Error acknowledging project",
 			fmt.Sprintf("Error acknowledging project: %s", err),
@@ -412,6 +456,9 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_project", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *ProjectResourceModel
 
 	// Read Terraform prior state data into the model
@@ -421,7 +468,14 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.DeleteProject(data.ProjectKey.ValueString())
+	ctx, cancel, diags := operationContext(ctx, data.Timeouts, operationDelete)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	err := r.projects.Delete(ctx, data.ProjectKey.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting project",
@@ -432,92 +486,8 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *ProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
+	ctx, span := startOperation(ctx, "immuta_project", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
 
-// CRUD methods
-
-func (r *ProjectResource) ListProjects() (projects Projects, err error) {
-	err = r.client.Get("/project", "", map[string]string{"noLimit": "false"}, &projects)
-	return
-}
-
-func (r *ProjectResource) FindProject(name string) (project Project, err error) {
-	projects := FindProjectsResponse{}
-	err = r.client.Get("/project", "", map[string]string{"searchText": name, "nameOnly": "true"}, &projects)
-	if err != nil {
-		return
-	}
-	project = projects.Hits[0]
-	return
-}
-
-func (r *ProjectResource) GetProject(id string) (project Project, err error) {
-	err = r.client.Get(fmt.Sprintf("/project/%s", id), "", nil, &project)
-	return
-}
-
-func (r *ProjectResource) DeleteProject(projectKey string) (err error) {
-	err = r.client.Delete(fmt.Sprintf("/api/v2/project/%s", projectKey), "", nil, nil)
-	return
-}
-
-func (r *ProjectResource) UpsertProject(project ProjectInput) (projectResponse ProjectResourceResponseV2, err error) {
-	err = r.client.Post("/api/v2/project", "", project, &projectResponse)
-	return
-}
-
-type AcknowledgePayload struct{}
-
-func (r *ProjectResource) AcknowledgeProject(projectId int, memberId int) (err error) {
-	payload := AcknowledgePayload{}
-	err = r.client.Post(fmt.Sprintf("/project/%d/members/%d/acknowledge", projectId, memberId), "", payload, nil)
-	return
-}
-
-// Domain specific types
-
-type ProjectInput struct {
-	Name               string                 `json:"name"`
-	ProjectKey         string                 `json:"projectKey"`
-	Description        string                 `json:"description,omitempty"`
-	Documentation      string                 `json:"documentation,omitempty"`
-	AllowMaskedJoins   bool                   `json:"allowMaskedJoins,omitempty"`
-	SubscriptionPolicy map[string]interface{} `json:"subscriptionPolicy,omitempty"`
-	Tags               []string               `json:"tags,omitempty"`
-	Purposes           []string               `json:"purposes,omitempty"`
-}
-
-type Project struct {
-	ProjectInput
-	Tags           []Tag     `json:"tags"`
-	Purposes       []Purpose `json:"purposes"`
-	Id             int       `json:"id"`
-	Status         string    `json:"status"`
-	Deleted        bool      `json:"deleted"`
-	SubscriptionId int       `json:"subscriptionId"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdateAt       time.Time `json:"updatedAt"`
-}
-
-type ProjectResourceResponseV2 struct {
-	DryRun                     bool `json:"dryRun"`
-	Creating                   bool `json:"creating"`
-	Updating                   bool `json:"updating"`
-	NumberOfDataSourcesAdded   int  `json:"numberOfDataSourcesAdded"`
-	NumberOfDataSourcesRemoved int  `json:"numberOfDataSourcesRemoved"`
-	CreatingWorkspace          bool `json:"creatingWorkspace"`
-	DeleteWorkspace            bool `json:"deletingWorkspace"`
-	ProjectId                  int  `json:"projectId"`
-}
-
-type Projects struct {
-	Projects []Project `json:"projects"`
-	Count    int       `json:"count"`
-}
-
-type FindProjectsResponse struct {
-	Hits   []Project `json:"hits"`
-	Facets struct{}  `json:"facets"`
-	Count  int       `json:"count"`
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
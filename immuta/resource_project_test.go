@@ -1,8 +1,13 @@
 package immuta
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
+	"strconv"
 	"testing"
 )
 
@@ -65,3 +70,64 @@ func testAccProjectConfig(desc string) string {
 	}
 `
 }
+
+func TestProject_lifecycle(t *testing.T) {
+	l := newLifecycle[ProjectResourceModel](t, NewProjectResource())
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		purposeId := state.NextId()
+		state.Purposes[purposeId] = &client.PurposeResponse{Id: purposeId}
+		state.Purposes[purposeId].Name = "Test Porpoise"
+		state.Purposes[purposeId].Acknowledgement = "I will only use this data for testing"
+		tagId := state.NextId()
+		state.Tags[tagId] = &client.TagList{Id: tagId, Name: "tf_acc_test"}
+	})
+
+	// the purpose has an acknowledgement, so create has to acknowledge it and retry
+	created := l.create(testProjectModel("desca"))
+	id, err := strconv.Atoi(created.Id.ValueString())
+	if err != nil {
+		t.Fatalf("expected a numeric id, got %q", created.Id.ValueString())
+	}
+
+	read := l.read(created)
+	if read.Description.ValueString() != "desca" || read.Purposes.Elements()[0] != types.StringValue("Test Porpoise") {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	planned := testProjectModel("descb")
+	planned.Id = read.Id
+	updated := l.update(read, planned)
+
+	imported := l.importState(updated.Id.ValueString())
+	if imported.ProjectKey.ValueString() != testProjectKey || imported.Description.ValueString() != "descb" || len(imported.Tags.Elements()) != 1 {
+		t.Errorf("unexpected state after import: %+v", imported)
+	}
+
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.Projects[id].Documentation = "changed outside Terraform"
+	})
+	drifted := l.read(updated)
+	if drifted.Documentation.ValueString() != "changed outside Terraform" {
+		t.Errorf("expected read to pick up the drifted documentation, got %q", drifted.Documentation.ValueString())
+	}
+
+	l.delete(drifted)
+	if l.read(drifted) != nil {
+		t.Error("expected a deleted project to be removed from the state")
+	}
+}
+
+func testProjectModel(desc string) *ProjectResourceModel {
+	return &ProjectResourceModel{
+		Id:                 types.StringUnknown(),
+		Name:               types.StringValue(testProjectName),
+		Description:        types.StringValue(desc),
+		ProjectKey:         types.StringValue(testProjectKey),
+		Documentation:      types.StringValue(testProjectDocumentation),
+		AllowMaskedJoins:   types.BoolNull(),
+		SubscriptionPolicy: types.MapNull(types.StringType),
+		Tags:               types.ListValueMust(types.StringType, []attr.Value{types.StringValue("tf_acc_test")}),
+		Purposes:           types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Test Porpoise")}),
+		Timeouts:           noTimeouts(),
+	}
+}
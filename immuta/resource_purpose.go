@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/immuta/terraform-provider-immuta/client"
 	"strconv"
-	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -23,7 +22,8 @@ func NewPurposeResource() resource.Resource {
 
 // PurposeResource defines the resource implementation.
 type PurposeResource struct {
-	client *client.ImmutaClient
+	purposes client.PurposesService
+	features client.FeatureChecker
 }
 
 // PurposeResourceModel describes the resource data model.
@@ -88,21 +88,25 @@ func (r *PurposeResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	immutaClient, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *immutaClient.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = immutaClient
+	r.purposes = immutaClient.Purposes()
+	r.features = immutaClient
 }
 
 func (r *PurposeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_purpose", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	// todo add check that the subpurpose name is actually a subpurpose of the parent purpose
 	var data *PurposeResourceModel
 
@@ -114,10 +118,10 @@ func (r *PurposeResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	// Actually create the purpose
-	var purposeResponse PurposeResourceResponseV2
+	var purposeResponse *client.PurposeResourceResponseV2
 
-	purposeInput := PurposeInput{
-		Purpose: Purpose{
+	purposeInput := client.PurposeInput{
+		Purpose: client.Purpose{
 			Name:            data.Name.ValueString(),
 			Description:     data.Description.ValueString(),
 			Acknowledgement: data.Acknowledgement.ValueString(),
@@ -125,7 +129,7 @@ func (r *PurposeResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	if data.Subpurposes.Elements() != nil && len(data.Subpurposes.Elements()) > 0 {
-		subpurposes := make([]Purpose, 0)
+		subpurposes := make([]client.Purpose, 0)
 		if diags := data.Subpurposes.ElementsAs(ctx, &subpurposes, false); diags != nil && diags.HasError() {
 			resp.Diagnostics.Append(diags...)
 			return
@@ -133,12 +137,14 @@ func (r *PurposeResource) Create(ctx context.Context, req resource.CreateRequest
 		purposeInput.Subpurposes = subpurposes
 	}
 
+	resp.Diagnostics.Append(featureWarning(r.features, client.FeatureV2Purpose)...)
+
 	// Do it twice as a workaround for a bug in the API where acknowledgement not updated first time (ops are idempotent)
 	for i := 0; i < 2; i++ {
-		pr, err := r.UpsertPurpose(purposeInput)
+		pr, err := r.purposes.Upsert(ctx, purposeInput)
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Client error",
+				clientErrorSummary("Client error", err),
 				fmt.Sprintf("Could not create purpose: %s", err),
 			)
 			return
@@ -158,6 +164,9 @@ func (r *PurposeResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *PurposeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_purpose", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	// todo fix the subpurpose name construction
 	var data *PurposeResourceModel
 
@@ -168,7 +177,12 @@ func (r *PurposeResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	purpose, err := r.GetPurpose(data.Id.String())
+	purpose, err := r.purposes.Get(ctx, data.Id.String())
+	if client.IsNotFound(err) {
+		// Purpose no longer exists, remove from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Client error",
@@ -194,7 +208,7 @@ func (r *PurposeResource) Read(ctx context.Context, req resource.ReadRequest, re
 		data.Description = types.StringValue(purpose.Description)
 	}
 
-	newSubpurposes, subpurposesDiags := updateListIfChanged[Purpose](ctx, data.Subpurposes, purpose.Subpurposes)
+	newSubpurposes, subpurposesDiags := updateListIfChanged[client.Purpose](ctx, data.Subpurposes, purpose.Subpurposes)
 	if subpurposesDiags != nil && subpurposesDiags.HasError() {
 		resp.Diagnostics.Append(subpurposesDiags...)
 		return
@@ -206,6 +220,9 @@ func (r *PurposeResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *PurposeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_purpose", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *PurposeResourceModel
 
 	// Read Terraform plan data into the model
@@ -215,8 +232,8 @@ func (r *PurposeResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	purposeInput := PurposeInput{
-		Purpose: Purpose{
+	purposeInput := client.PurposeInput{
+		Purpose: client.Purpose{
 			Name:            data.Name.ValueString(),
 			Description:     data.Description.ValueString(),
 			Acknowledgement: data.Acknowledgement.ValueString(),
@@ -224,7 +241,7 @@ func (r *PurposeResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	if data.Subpurposes.Elements() != nil && len(data.Subpurposes.Elements()) > 0 {
-		subpurposes := make([]Purpose, 0)
+		subpurposes := make([]client.Purpose, 0)
 		if diags := data.Subpurposes.ElementsAs(ctx, &subpurposes, false); diags != nil && diags.HasError() {
 			resp.Diagnostics.Append(diags...)
 			return
@@ -232,10 +249,11 @@ func (r *PurposeResource) Update(ctx context.Context, req resource.UpdateRequest
 		purposeInput.Subpurposes = subpurposes
 	}
 
-	purposeResponse, err := r.UpsertPurpose(purposeInput)
+	resp.Diagnostics.Append(featureWarning(r.features, client.FeatureV2Purpose)...)
+	purposeResponse, err := r.purposes.Upsert(ctx, purposeInput)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Client error",
+			clientErrorSummary("Client error", err),
 			fmt.Sprintf("Could not update purpose: %s", err),
 		)
 		return
@@ -253,6 +271,9 @@ func (r *PurposeResource) Update(ctx context.Context, req resource.UpdateRequest
 }
 
 func (r *PurposeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_purpose", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *PurposeResourceModel
 
 	// Read Terraform prior state data into the model
@@ -262,7 +283,7 @@ func (r *PurposeResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.DeletePurpose(data.Id.String())
+	err := r.purposes.Delete(ctx, data.Id.String())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Client error",
@@ -273,64 +294,8 @@ func (r *PurposeResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *PurposeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
-
-// CRUD methods
-
-func (r *PurposeResource) ListPurposes() (purposes Purposes, err error) {
-	err = r.client.Get("/governance/purpose", "", map[string]string{"noLimit": "false"}, &purposes)
-	return
-}
-
-func (r *PurposeResource) GetPurpose(id string) (purpose PurposeResponse, err error) {
-	err = r.client.Get(fmt.Sprintf("/governance/purpose/%s", id), "", map[string]string{"includeSubpurposes": "true"}, &purpose)
-	return
-}
+	ctx, span := startOperation(ctx, "immuta_purpose", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
 
-func (r *PurposeResource) DeletePurpose(id string) (err error) {
-	err = r.client.Delete(fmt.Sprintf("/governance/purpose/%s", id), "", nil, nil)
-	return
-}
-
-func (r *PurposeResource) UpsertPurpose(purpose PurposeInput) (purposeResponse PurposeResourceResponseV2, err error) {
-	err = r.client.Post("/api/v2/purpose", "", purpose, &purposeResponse)
-	return
-}
-
-// Domain specific objects
-
-type Purpose struct {
-	Name            string `json:"name" tfsdk:"name"`
-	Description     string `json:"description" tfsdk:"description"`
-	Acknowledgement string `json:"acknowledgement" tfsdk:"acknowledgement"`
-}
-
-type PurposeInput struct {
-	Purpose
-	Subpurposes []Purpose `json:"subpurposes,omitempty"`
-}
-
-type PurposeResponse struct {
-	PurposeInput
-	Id                     int         `json:"id"`
-	AddedByProfile         int         `json:"addedByProfile"`
-	DisplayAcknowledgement bool        `json:"displayAcknowledgement"`
-	Deleted                bool        `json:"deleted"`
-	SystemGenerated        bool        `json:"systemGenerated"`
-	PolicyMetadata         interface{} `json:"policyMetadata"`
-	CreatedAt              time.Time   `json:"createdAt"`
-	UpdatedAt              time.Time   `json:"updatedAt"`
-}
-
-type PurposeResourceResponseV2 struct {
-	DryRun    bool `json:"dryRun"`
-	Creating  bool `json:"creating"`
-	Updating  bool `json:"updating"`
-	PurposeId int  `json:"purposeId"`
-}
-
-type Purposes struct {
-	Purposes []PurposeResponse `json:"purposes"`
-	Count    int               `json:"count"`
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
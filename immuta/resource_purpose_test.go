@@ -1,9 +1,17 @@
 package immuta
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/client"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
+	"path/filepath"
 	"testing"
 )
 
@@ -14,7 +22,7 @@ const testResourceAcknowledgement = "I will not use this purpose as it is a test
 func TestAccPurpose_basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: testAccCassetteProviderFactories(t),
 		CheckDestroy:             testAccCheckPurposeDestroy,
 		Steps: []resource.TestStep{
 			// test create and read
@@ -96,3 +104,140 @@ func testAccPurposeConfigNoOptionalParams() string {
 	}
 `, testResourceName, testResourceDescription)
 }
+
+func TestPurpose_lifecycle(t *testing.T) {
+	l := newLifecycle[PurposeResourceModel](t, NewPurposeResource())
+
+	planned := testPurposeModel(t, "a")
+	created := l.create(planned)
+	if created.Id.IsUnknown() || created.Id.IsNull() {
+		t.Fatal("expected create to set the id")
+	}
+	id64, _ := created.Id.ValueBigFloat().Int64()
+	id := int(id64)
+
+	read := l.read(created)
+	if read.Description.ValueString() != testResourceDescription+" a" || len(read.Subpurposes.Elements()) != 2 {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	planned = testPurposeModel(t, "b")
+	planned.Id = read.Id
+	updated := l.update(read, planned)
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		if state.Purposes[id].Description != testResourceDescription+" b" {
+			t.Errorf("expected the update to reach Immuta, got %q", state.Purposes[id].Description)
+		}
+		state.Purposes[id].Description = "changed outside Terraform"
+	})
+
+	drifted := l.read(updated)
+	if drifted.Description.ValueString() != "changed outside Terraform" {
+		t.Errorf("expected read to pick up the drifted description, got %q", drifted.Description.ValueString())
+	}
+
+	l.delete(drifted)
+	if l.read(drifted) != nil {
+		t.Error("expected a deleted purpose to be removed from the state")
+	}
+}
+
+// TestPurpose_replaysCassette records a purpose's lifecycle against the fake and replays it with the fake gone,
+// the offline path acceptance tests take once their cassettes are recorded against a tenant
+func TestPurpose_replaysCassette(t *testing.T) {
+	l := newLifecycle[PurposeResourceModel](t, NewPurposeResource())
+	path := filepath.Join(t.TempDir(), "purpose.json")
+
+	recorder, err := client.NewCassette(path, client.CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.configure(l.server.Client(client.WithCassette(recorder)))
+	recorded := l.read(l.create(testPurposeModel(t, "a")))
+	l.delete(recorded)
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := client.NewCassette(path, client.CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.configure(l.server.Client(client.WithCassette(player)))
+	l.server.Close()
+
+	replayed := l.read(l.create(testPurposeModel(t, "a")))
+	if !replayed.Id.Equal(recorded.Id) || !replayed.Description.Equal(recorded.Description) {
+		t.Errorf("expected the replayed purpose %+v to match the recorded one %+v", replayed, recorded)
+	}
+	l.delete(replayed)
+}
+
+func TestPurpose_logsResourceFields(t *testing.T) {
+	l := newLifecycle[PurposeResourceModel](t, NewPurposeResource())
+	output := bytes.Buffer{}
+	l.ctx = tflogtest.RootLogger(l.ctx, &output)
+
+	l.create(testPurposeModel(t, "a"))
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	for _, entry := range entries {
+		if entry["@message"] != "Immuta request succeeded" {
+			continue
+		}
+		requests++
+		if entry["resource"] != "immuta_purpose" || entry["operation"] != operationCreate {
+			t.Errorf("expected the request log to carry the resource and operation, got %v", entry)
+		}
+	}
+	if requests == 0 {
+		t.Fatalf("expected the requests made by create to be logged:\n%v", entries)
+	}
+}
+
+func testPurposeModel(t *testing.T, descriptionAppend string) *PurposeResourceModel {
+	subpurposeType := types.ObjectType{AttrTypes: map[string]attr.Type{
+		"name":            types.StringType,
+		"description":     types.StringType,
+		"acknowledgement": types.StringType,
+	}}
+	subpurposes, diags := types.ListValueFrom(context.Background(), subpurposeType, []client.Purpose{
+		{Name: testResourceName + ".subpurpose 1", Description: "subpurpose 1 description", Acknowledgement: "subpurpose 1 acknowledgement"},
+		{Name: testResourceName + ".subpurpose 2", Description: "subpurpose 2 description " + descriptionAppend, Acknowledgement: "subpurpose 2 acknowledgement"},
+	})
+	if diags.HasError() {
+		t.Fatalf("could not build subpurposes: %v", diags)
+	}
+
+	return &PurposeResourceModel{
+		Id:              types.NumberUnknown(),
+		Name:            types.StringValue(testResourceName),
+		Description:     types.StringValue(testResourceDescription + " " + descriptionAppend),
+		Acknowledgement: types.StringValue(testResourceAcknowledgement),
+		Subpurposes:     subpurposes,
+	}
+}
+
+func TestPurpose_olderReleaseOnlyWarns(t *testing.T) {
+	l := newLifecycle[PurposeResourceModel](t, NewPurposeResource())
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		state.Release = "2022.1.0"
+	})
+	features := l.server.Client()
+	if err := features.DetectRelease(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l.resource.(*PurposeResource).features = features
+
+	diags := l.tryCreate(testPurposeModel(t, "a"))
+	if diags.HasError() {
+		t.Fatalf("expected the purpose to be sent to Immuta, got %v", diags)
+	}
+	if diags.WarningsCount() != 1 || diags.Warnings()[0].Summary() != "Immuta release may be too old" {
+		t.Errorf("expected a warning about the release, got %v", diags)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/immuta/terraform-provider-immuta/client"
 	"strconv"
-	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -24,7 +23,7 @@ func NewTagResource() resource.Resource {
 
 // TagResource defines the resource implementation.
 type TagResource struct {
-	client *client.ImmutaClient
+	tags client.TagsService
 }
 
 // TagResourceModel describes the resource data model.
@@ -69,21 +68,24 @@ func (r *TagResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	immutaClient, ok := req.ProviderData.(*client.ImmutaClient)
+	immutaClient, ok := req.ProviderData.(client.API)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *immutaClient.ImmutaClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.API, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = immutaClient
+	r.tags = immutaClient.Tags()
 }
 
 func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperation(ctx, "immuta_tag", operationCreate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *TagResourceModel
 
 	// Read Terraform plan data into the model
@@ -93,21 +95,21 @@ func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	tagInput := TagInput{
-		Tags: []TagSingular{
+	tagInput := client.TagInput{
+		Tags: []client.TagSingular{
 			{Name: data.Name.ValueString()},
 		},
 	}
 
 	if data.RootTag.ValueString() != "" {
 
-		tagInput.RootTag = &RootTag{
+		tagInput.RootTag = &client.RootTag{
 			Name:            data.RootTag.ValueString(),
 			DeleteHierarchy: false,
 		}
 	}
 
-	tagResponse, err := r.CreateTag(ctx, tagInput)
+	tagResponse, err := r.tags.Create(ctx, tagInput)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating tag", err.Error())
 		return
@@ -120,6 +122,9 @@ func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, re
 }
 
 func (r *TagResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperation(ctx, "immuta_tag", operationRead)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *TagResourceModel
 
 	// Read Terraform prior state data into the model
@@ -129,7 +134,7 @@ func (r *TagResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
-	tag, err := r.GetTag(ctx, data.Name.ValueString())
+	tag, err := r.tags.Get(ctx, data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error getting tag", err.Error())
 		return
@@ -146,6 +151,9 @@ func (r *TagResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 }
 
 func (r *TagResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperation(ctx, "immuta_tag", operationUpdate)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *TagResourceModel
 
 	// Read Terraform plan data into the model
@@ -163,6 +171,9 @@ func (r *TagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 }
 
 func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperation(ctx, "immuta_tag", operationDelete)
+	defer endOperation(span, &resp.Diagnostics)
+
 	var data *TagResourceModel
 
 	// Read Terraform prior state data into the model
@@ -172,7 +183,7 @@ func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
-	err := r.DeleteTag(ctx, data.Name.ValueString())
+	err := r.tags.Delete(ctx, data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting tag", err.Error())
 		return
@@ -180,103 +191,8 @@ func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 }
 
 func (r *TagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
-
-// CRUD methods
-
-func (r *TagResource) CreateTag(_ context.Context, tagInput TagInput) (response *TagCreateResponse, err error) {
-	responses := make([]TagCreateResponse, 0)
-	err = r.client.Post("/tag", "", tagInput, &responses)
-	if responses == nil || len(responses) == 0 {
-		return nil, fmt.Errorf("no response from create tag")
-	}
-	response = &responses[0]
-	return
-}
-
-// GetTag returns nil if tag does not exist
-func (r *TagResource) GetTag(_ context.Context, name string) (*TagList, error) {
-	// have to search for the tag because the API doesn't support getting a tag by name/id
-	tags := make([]TagList, 0)
-	err := r.client.Get("/tag", "", map[string]string{"searchText": name}, &tags)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(tags) == 0 {
-		return nil, nil
-	}
-
-	for _, tag := range tags {
-		if tag.Name == name {
-			return &tag, nil
-		}
-	}
-
-	return nil, nil
-}
-
-func (r *TagResource) DeleteTag(_ context.Context, name string) (err error) {
-	err = r.client.Delete(fmt.Sprintf("/tag/%s", name), "", nil, nil)
-	return
-}
+	ctx, span := startOperation(ctx, "immuta_tag", operationImport)
+	defer endOperation(span, &resp.Diagnostics)
 
-// Domain specific types
-
-type TagInput struct {
-	Tags     []TagSingular `json:"tags"`
-	*RootTag `json:"rootTag,omitempty"`
-}
-
-//// MarshalJSON omit rootTag if empty
-//func (t TagInput) MarshalJSON() ([]byte, error) {
-//	if t.RootTag.Name == "" {
-//		return json.Marshal(struct {
-//			Tags []TagSingular `json:"tags"`
-//		}{
-//			Tags: t.Tags,
-//		})
-//	}
-//	return json.Marshal(t)
-//}
-
-type TagSingular struct {
-	Name string `json:"name"`
-}
-
-type Tag struct {
-	Id          int    `json:"id"`
-	Name        string `json:"name"`
-	DisplayName string `json:"displayName"`
-	Source      string `json:"source"`
-	Context     string `json:"context"`
-	AddedBy     int    `json:"addedBy"`
-	Deleted     bool   `json:"deleted"`
-}
-
-type RootTag struct {
-	Name            string `json:"name,omitempty"`
-	DeleteHierarchy bool   `json:"deleteHierarchy,omitempty"`
-}
-
-type TagCreateResponse struct {
-	Id            int       `json:"id"`
-	Name          string    `json:"name"`
-	Source        string    `json:"source"`
-	Deleted       bool      `json:"deleted"`
-	SystemCreated bool      `json:"systemCreated"`
-	CreatedBy     int       `json:"createdBy"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
-}
-
-type TagList struct {
-	Name          string `json:"name"`
-	HasLeafNodes  bool   `json:"hasLeafNodes"`
-	Source        string `json:"source"`
-	Id            int    `json:"id"`
-	Deleted       bool   `json:"deleted"`
-	SystemCreated bool   `json:"systemCreated"`
-	DisplayName   string `json:"displayName"`
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
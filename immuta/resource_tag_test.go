@@ -2,8 +2,10 @@ package immuta
 
 import (
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/immuta/terraform-provider-immuta/internal/fakeimmuta"
 	"testing"
 )
 
@@ -56,3 +58,42 @@ func testAccTagConfigWithRoot(tagName string, rootTag string) string {
 func fullTagName(tagName string, rootTag string) string {
 	return testTagPrefix + rootTag + "." + testTagPrefix + tagName
 }
+
+func TestTag_lifecycle(t *testing.T) {
+	l := newLifecycle[TagResourceModel](t, NewTagResource())
+
+	root := l.create(&TagResourceModel{
+		Id:      types.StringUnknown(),
+		Name:    types.StringValue(testTagPrefix + "a"),
+		RootTag: types.StringNull(),
+	})
+	child := l.create(&TagResourceModel{
+		Id:      types.StringUnknown(),
+		Name:    types.StringValue(fullTagName("b", "a")),
+		RootTag: types.StringValue(testTagPrefix + "a"),
+	})
+	if root.Id == child.Id {
+		t.Errorf("expected tags to get different ids, got %s", root.Id)
+	}
+
+	if read := l.read(child); read == nil || read.Name.ValueString() != fullTagName("b", "a") {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	// delete the root tag outside Terraform
+	l.server.Mutate(func(state *fakeimmuta.State) {
+		for id, tag := range state.Tags {
+			if tag.Name == testTagPrefix+"a" {
+				delete(state.Tags, id)
+			}
+		}
+	})
+	if l.read(root) != nil {
+		t.Error("expected a tag deleted outside Terraform to be removed from the state")
+	}
+
+	l.delete(child)
+	if l.read(child) != nil {
+		t.Error("expected a deleted tag to be removed from the state")
+	}
+}
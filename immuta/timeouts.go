@@ -0,0 +1,40 @@
+package immuta
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"time"
+)
+
+// defaultOperationTimeout bounds creating, updating and deleting when the timeouts block does not set it. Reads
+// have no default, they are only bounded by the provider's request_timeout unless the block sets one.
+const defaultOperationTimeout = 20 * time.Minute
+
+// operationContext returns a context with the deadline from the timeouts block for the given operation,
+// the cancel function must be called once the operation is done
+func operationContext(ctx context.Context, value timeouts.Value, operation string) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	var timeout time.Duration
+	var diags diag.Diagnostics
+
+	switch operation {
+	case operationCreate:
+		timeout, diags = value.Create(ctx, defaultOperationTimeout)
+	case operationUpdate:
+		timeout, diags = value.Update(ctx, defaultOperationTimeout)
+	case operationDelete:
+		timeout, diags = value.Delete(ctx, defaultOperationTimeout)
+	default:
+		timeout, diags = value.Read(ctx, 0)
+	}
+	if diags.HasError() {
+		return ctx, func() {}, diags
+	}
+
+	if timeout == 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, diags
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, diags
+}
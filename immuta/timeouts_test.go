@@ -0,0 +1,55 @@
+package immuta
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+	"time"
+)
+
+// noTimeouts is a timeouts block that is not configured
+func noTimeouts() timeouts.Value {
+	return timeouts.Value{Object: types.ObjectNull(testTimeoutsType().AttrTypes)}
+}
+
+// testTimeouts is a timeouts block that only sets the timeout of the given operation
+func testTimeouts(operation, timeout string) timeouts.Value {
+	values := map[string]attr.Value{}
+	for name := range testTimeoutsType().AttrTypes {
+		values[name] = types.StringNull()
+	}
+	values[operation] = types.StringValue(timeout)
+	return timeouts.Value{Object: types.ObjectValueMust(testTimeoutsType().AttrTypes, values)}
+}
+
+func testTimeoutsType() timeouts.Type {
+	return timeouts.BlockAll(context.Background()).Type().(timeouts.Type)
+}
+
+func TestOperationContext_defaults(t *testing.T) {
+	for _, operation := range []string{operationCreate, operationUpdate, operationDelete} {
+		ctx, cancel, diags := operationContext(context.Background(), noTimeouts(), operation)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > defaultOperationTimeout {
+			t.Errorf("expected %s to default to %s, got %v", operation, defaultOperationTimeout, deadline)
+		}
+		cancel()
+	}
+
+	ctx, cancel, _ := operationContext(context.Background(), noTimeouts(), operationRead)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); ok {
+		t.Errorf("expected read to have no default timeout, got %v", deadline)
+	}
+
+	ctx, cancel, _ = operationContext(context.Background(), testTimeouts(operationRead, "1m"), operationRead)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("expected a configured read timeout to set a deadline")
+	}
+}
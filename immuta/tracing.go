@@ -0,0 +1,39 @@
+package immuta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of resource operations, the client's request spans are their children
+const tracerName = "github.com/immuta/terraform-provider-immuta/immuta"
+
+// startOperation starts a span for a Terraform operation on a resource and attaches the resource type and operation
+// to its logs. endOperation must be deferred with the response's diagnostics.
+func startOperation(ctx context.Context, resourceType, operation string) (context.Context, trace.Span) {
+	ctx = withResourceLogFields(ctx, resourceType, operation)
+	return otel.Tracer(tracerName).Start(ctx, fmt.Sprintf("%s.%s", resourceType, operation),
+		trace.WithAttributes(
+			attribute.String("tf.resource_type", resourceType),
+			attribute.String("tf.operation", operation),
+		),
+	)
+}
+
+// endOperation records the errors reported by the operation on its span and ends it
+func endOperation(span trace.Span, diags *diag.Diagnostics) {
+	errs := diags.Errors()
+	for _, d := range errs {
+		span.RecordError(errors.New(d.Detail()), trace.WithAttributes(attribute.String("tf.diagnostic.summary", d.Summary())))
+	}
+	if len(errs) > 0 {
+		span.SetStatus(codes.Error, errs[0].Summary())
+	}
+	span.End()
+}
@@ -2,6 +2,9 @@ package immuta
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/numberplanmodifier"
@@ -9,6 +12,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/immuta/terraform-provider-immuta/client"
 	"math/big"
 	"reflect"
 )
@@ -33,6 +38,143 @@ func numberResourceId() schema.NumberAttribute {
 	}
 }
 
+// defaultString plans a default for an optional and computed attribute left out of the configuration
+type defaultString string
+
+func (m defaultString) Description(_ context.Context) string {
+	return fmt.Sprintf("defaults to %q", string(m))
+}
+
+func (m defaultString) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m defaultString) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.ConfigValue.IsNull() {
+		resp.PlanValue = types.StringValue(string(m))
+	}
+}
+
+// defaultInt64 plans a default for an optional and computed attribute left out of the configuration
+type defaultInt64 int64
+
+func (m defaultInt64) Description(_ context.Context) string {
+	return fmt.Sprintf("defaults to %d", int64(m))
+}
+
+func (m defaultInt64) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m defaultInt64) PlanModifyInt64(_ context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.ConfigValue.IsNull() {
+		resp.PlanValue = types.Int64Value(int64(m))
+	}
+}
+
+// featureWarning warns when the tenant may be too old for a feature the resource relies on. The releases features
+// were introduced in are estimates, the request is still sent and Immuta left to reject it.
+func featureWarning(features client.FeatureChecker, feature client.Feature) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if features == nil {
+		return diags
+	}
+	if err := features.CheckFeature(feature); err != nil {
+		diags.AddWarning("Immuta release may be too old", fmt.Sprintf("%s. Immuta will reject the request if the endpoint is missing.", err))
+	}
+	return diags
+}
+
+// clientErrorSummary replaces the summary with a clear one when the operation ran out of time
+func clientErrorSummary(summary string, err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "Timed out waiting for Immuta"
+	}
+	return summary
+}
+
+// isFullyKnown reports whether the values can be sent to Immuta, i.e. none of them depend on resources not yet applied
+func isFullyKnown(ctx context.Context, values ...attr.Value) bool {
+	for _, value := range values {
+		terraformValue, err := value.ToTerraformValue(ctx)
+		if err != nil || !terraformValue.IsFullyKnown() {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshedValue reconciles a value read back from Immuta with the prior state. Immuta reports optional attributes
+// that were never set as zero values, those stay null so they do not show up as a diff against the configuration.
+func refreshedValue(ctx context.Context, prior, refreshed attr.Value) attr.Value {
+	if prior.IsNull() && isZeroValue(refreshed) {
+		return prior
+	}
+	if refreshed.IsNull() || refreshed.IsUnknown() {
+		return refreshed
+	}
+
+	switch refreshed := refreshed.(type) {
+	case types.Object:
+		priorObject, _ := prior.(types.Object)
+		attributes := map[string]attr.Value{}
+		for name, value := range refreshed.Attributes() {
+			priorValue, ok := priorObject.Attributes()[name]
+			if !ok {
+				priorValue = nullValue(ctx, refreshed.AttributeTypes(ctx)[name])
+			}
+			attributes[name] = refreshedValue(ctx, priorValue, value)
+		}
+		return types.ObjectValueMust(refreshed.AttributeTypes(ctx), attributes)
+	case types.List:
+		priorList, _ := prior.(types.List)
+		elements := make([]attr.Value, 0, len(refreshed.Elements()))
+		for i, value := range refreshed.Elements() {
+			priorValue := nullValue(ctx, refreshed.ElementType(ctx))
+			if i < len(priorList.Elements()) {
+				priorValue = priorList.Elements()[i]
+			}
+			elements = append(elements, refreshedValue(ctx, priorValue, value))
+		}
+		return types.ListValueMust(refreshed.ElementType(ctx), elements)
+	}
+
+	return refreshed
+}
+
+// isZeroValue reports whether a value is null or the zero value of its type, objects are zero when all their
+// attributes are
+func isZeroValue(value attr.Value) bool {
+	if value.IsNull() {
+		return true
+	}
+
+	switch value := value.(type) {
+	case types.String:
+		return value.ValueString() == ""
+	case types.Bool:
+		return !value.ValueBool()
+	case types.Number:
+		return value.ValueBigFloat() != nil && value.ValueBigFloat().Sign() == 0
+	case types.List:
+		return len(value.Elements()) == 0
+	case types.Object:
+		for _, attribute := range value.Attributes() {
+			if !isZeroValue(attribute) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func nullValue(ctx context.Context, attributeType attr.Type) attr.Value {
+	value, _ := attributeType.ValueFromTerraform(ctx, tftypes.NewValue(attributeType.TerraformType(ctx), nil))
+	return value
+}
+
 func intToNumberValue(i int) types.Number {
 	return types.NumberValue(big.NewFloat(float64(i)))
 }
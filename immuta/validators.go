@@ -0,0 +1,151 @@
+package immuta
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"strings"
+)
+
+// stringCheck validates a string attribute with a predicate, so misconfigurations are reported at plan time
+// rather than by Immuta when the plan is applied
+type stringCheck struct {
+	summary     string
+	description string
+	valid       func(value string) bool
+}
+
+func (v stringCheck) Description(_ context.Context) string {
+	return v.description
+}
+
+func (v stringCheck) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringCheck) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !v.valid(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(req.Path, v.summary, fmt.Sprintf("%s.", v.Description(ctx)))
+	}
+}
+
+func stringOneOf(values ...string) stringCheck {
+	return stringCheck{
+		summary:     "Invalid value",
+		description: fmt.Sprintf("value must be one of %q", values),
+		valid: func(value string) bool {
+			for _, allowed := range values {
+				if value == allowed {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func stringNotEmpty() stringCheck {
+	return stringCheck{
+		summary:     "Invalid value",
+		description: "value must not be empty",
+		valid:       func(value string) bool { return strings.TrimSpace(value) != "" },
+	}
+}
+
+func stringHasPrefix(prefix string) stringCheck {
+	return stringCheck{
+		summary:     "Invalid value",
+		description: fmt.Sprintf("value must start with %q", prefix),
+		valid:       func(value string) bool { return strings.HasPrefix(value, prefix) },
+	}
+}
+
+// hostname rejects URLs, the handlers take the scheme and port from their own attributes
+func hostname() stringCheck {
+	return stringCheck{
+		summary:     "Invalid hostname",
+		description: "value must be a hostname such as \"account.snowflakecomputing.com\", without a scheme, port or path",
+		valid: func(value string) bool {
+			return value != "" && !strings.ContainsAny(value, ":/ ")
+		},
+	}
+}
+
+// int64Between checks that a number lies within an inclusive range
+type int64Between struct {
+	min, max int64
+}
+
+func (v int64Between) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be between %d and %d", v.min, v.max)
+}
+
+func (v int64Between) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64Between) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value < v.min || value > v.max {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid value", fmt.Sprintf("%s, got %d.", v.Description(ctx), value))
+	}
+}
+
+// nameTemplateFormat checks the placeholders of a name template format, typos would otherwise only be rejected by
+// Immuta or end up in the names
+type nameTemplateFormat struct {
+	// required placeholders keep the names unique
+	required []string
+	// forbidden placeholders vary within what the format names, e.g. the tables of a schema
+	forbidden []string
+}
+
+func (v nameTemplateFormat) Description(_ context.Context) string {
+	description := "value must only use the <DATABASE>, <SCHEMA> and <TABLENAME> placeholders, in upper, lower or capitalized case"
+	for _, placeholder := range v.required {
+		description += fmt.Sprintf(", and include <%s>", placeholder)
+	}
+	return description
+}
+
+func (v nameTemplateFormat) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v nameTemplateFormat) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	placeholders, err := templatePlaceholders(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid name template", fmt.Sprintf("%s.", err))
+		return
+	}
+
+	used := map[string]bool{}
+	for _, placeholder := range placeholders {
+		// a placeholder in the wrong case is reported once, not as missing as well
+		used[strings.ToUpper(placeholder)] = true
+		if !validPlaceholder(placeholder) {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid name template", fmt.Sprintf("<%s> is not a placeholder, %s.", placeholder, v.Description(ctx)))
+		}
+	}
+	for _, placeholder := range v.forbidden {
+		if used[placeholder] {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid name template", fmt.Sprintf("<%s> cannot be used in this format, it names what the tables share.", placeholder))
+		}
+	}
+	for _, placeholder := range v.required {
+		if !used[placeholder] {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid name template", fmt.Sprintf("The format must include <%s> for the names to be unique.", placeholder))
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package fakeimmuta
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+func (s *Server) routeBim() {
+	s.routeBimUsers()
+	s.routeBimGroups()
+	s.routeBimAttributes()
+}
+
+func (s *Server) routeBimUsers() {
+	// lists users of the built-in IAM, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodGet, "/bim/iam/bim/user", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ids := make([]string, 0, len(s.state.Users))
+		for id := range s.state.Users {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		users := make([]client.BimUser, 0, len(ids))
+		for _, id := range ids {
+			users = append(users, *s.state.Users[id])
+		}
+		writeJSON(w, client.BimUsers{Users: paginate(r, users), Count: len(users)})
+	})
+
+	// creates a user of the built-in IAM, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodPost, "/bim/iam/bim/user", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		input := client.BimUserInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+		if input.Userid == "" {
+			writeError(w, http.StatusBadRequest, "userid is required")
+			return
+		}
+		if _, ok := s.state.Users[input.Userid]; ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("User %s already exists", input.Userid))
+			return
+		}
+
+		user := &client.BimUser{Userid: input.Userid, Iamid: client.DefaultIamId}
+		user.Profile.BimUserProfileInput = input.Profile
+		s.state.Users[user.Userid] = user
+
+		response := client.BimUserCreateResponse{}
+		response.NewUser.BimUser = *user
+		writeJSON(w, response)
+	})
+
+	// updates a user's profile, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodPut, "/bim/iam/bim/user/{userid}/profile", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		user, ok := s.state.Users[params["userid"]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		profile := client.BimUserProfile{}
+		if !decode(w, r, &profile) {
+			return
+		}
+		// fields left out of the update keep their value
+		if profile.Name != "" {
+			user.Profile.Name = profile.Name
+		}
+		if profile.Email != "" {
+			user.Profile.Email = profile.Email
+		}
+		user.Profile.ExternalUserIds = profile.ExternalUserIds
+		writeJSON(w, user)
+	})
+
+	// deletes a user of the built-in IAM, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodDelete, "/bim/iam/bim/user/{userid}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if _, ok := s.state.Users[params["userid"]]; !ok {
+			writeError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		delete(s.state.Users, params["userid"])
+		delete(s.state.Authorizations, AuthorizationsKey(client.DefaultIamId, "user", params["userid"]))
+		writeJSON(w, map[string]bool{"success": true})
+	})
+}
+
+func (s *Server) routeBimGroups() {
+	// creates a group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodPost, "/bim/group", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		input := client.BimGroupInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+		if input.Name == "" {
+			writeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		now := time.Now()
+		group := &client.BimGroup{BimGroupInput: input, Id: s.state.NextId(), CreatedAt: now, UpdatedAt: now}
+		s.state.Groups[group.Id] = group
+		writeJSON(w, group)
+	})
+
+	// reads a group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodGet, "/bim/group/{id}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		group, ok := s.group(w, params)
+		if !ok {
+			return
+		}
+		writeJSON(w, group)
+	})
+
+	// updates a group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodPut, "/bim/group/{id}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		group, ok := s.group(w, params)
+		if !ok {
+			return
+		}
+		profile := client.BimGroupProfile{}
+		if !decode(w, r, &profile) {
+			return
+		}
+		group.Name = profile.Name
+		group.Email = profile.Email
+		group.Description = profile.Description
+		group.UpdatedAt = time.Now()
+		writeJSON(w, group)
+	})
+
+	// deletes a group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodDelete, "/bim/group/{id}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		group, ok := s.group(w, params)
+		if !ok {
+			return
+		}
+		delete(s.state.Groups, group.Id)
+		delete(s.state.GroupUsers, group.Id)
+		delete(s.state.Authorizations, AuthorizationsKey(group.IamId, "group", strconv.Itoa(group.Id)))
+		writeJSON(w, map[string]bool{"success": true})
+	})
+
+	// lists the members of a group with hits and count, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodGet, "/bim/group/{id}/user", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		group, ok := s.group(w, params)
+		if !ok {
+			return
+		}
+		members := s.state.GroupUsers[group.Id]
+		writeJSON(w, client.BimGroupUsers{Hits: paginate(r, members), Count: len(members)})
+	})
+
+	// adds a member to a group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodPost, "/bim/group/{id}/user", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		group, ok := s.group(w, params)
+		if !ok {
+			return
+		}
+		input := client.UserInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+		for _, member := range s.state.GroupUsers[group.Id] {
+			if member.UserId == input.UserId && member.IamId == input.IamId {
+				writeError(w, http.StatusConflict, fmt.Sprintf("User %s is already a member of the group", input.UserId))
+				return
+			}
+		}
+
+		now := time.Now()
+		member := client.BimGroupUser{
+			Id:        s.state.NextId(),
+			Group:     group.Id,
+			UserId:    input.UserId,
+			IamId:     input.IamId,
+			Profile:   client.BimGroupUserProfile{Id: s.state.NextId(), CreatedAt: now, UpdatedAt: now},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if user, ok := s.state.Users[input.UserId]; ok {
+			member.Profile.Name = user.Profile.Name
+			member.Profile.Email = user.Profile.Email
+		}
+		s.state.GroupUsers[group.Id] = append(s.state.GroupUsers[group.Id], member)
+
+		writeJSON(w, client.GroupUserResponse{Id: member.Id, Group: group.Id, Profile: member.Profile.Id, CreatedAt: now, UpdatedAt: now})
+	})
+
+	// removes a member from a group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/#remove-a-user-or-groups-attribute
+	s.handle(http.MethodDelete, "/bim/group/{id}/user/{memberId}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		group, ok := s.group(w, params)
+		if !ok {
+			return
+		}
+		memberId, ok := intParam(w, params, "memberId")
+		if !ok {
+			return
+		}
+		members := s.state.GroupUsers[group.Id]
+		for i, member := range members {
+			if member.Id == memberId {
+				s.state.GroupUsers[group.Id] = append(members[:i:i], members[i+1:]...)
+				writeJSON(w, map[string]bool{"success": true})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Group member not found")
+	})
+}
+
+// group looks up the group named by the id parameter, responding with a 404 if it does not exist
+func (s *Server) group(w http.ResponseWriter, params map[string]string) (*client.BimGroup, bool) {
+	id, ok := intParam(w, params, "id")
+	if !ok {
+		return nil, false
+	}
+	group, ok := s.state.Groups[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Group not found")
+		return nil, false
+	}
+	return group, true
+}
+
+func (s *Server) routeBimAttributes() {
+	// reads the authorizations of a user or group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodGet, "/bim/iam/{iamId}/{modelType}/{modelId}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if !s.modelExists(params["iamId"], params["modelType"], params["modelId"]) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", params["modelType"], params["modelId"]))
+			return
+		}
+
+		authorizations := s.state.Authorizations[AuthorizationsKey(params["iamId"], params["modelType"], params["modelId"])]
+		if authorizations == nil {
+			authorizations = map[string][]string{}
+		}
+		response := map[string]interface{}{"bimAuthorizations": authorizations}
+		if params["modelType"] == "user" {
+			if user, ok := s.state.Users[params["modelId"]]; ok {
+				response["userid"] = user.Userid
+				response["iamid"] = user.Iamid
+				response["profile"] = user.Profile
+			}
+		}
+		writeJSON(w, response)
+	})
+
+	// adds an attribute value to a user or group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/
+	s.handle(http.MethodPut, "/bim/iam/{iamId}/{modelType}/{modelId}/authorizations/{key}/{value}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if !s.modelExists(params["iamId"], params["modelType"], params["modelId"]) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", params["modelType"], params["modelId"]))
+			return
+		}
+
+		key := AuthorizationsKey(params["iamId"], params["modelType"], params["modelId"])
+		if s.state.Authorizations[key] == nil {
+			s.state.Authorizations[key] = map[string][]string{}
+		}
+		// Immuta stores attribute names in lower case
+		name := strings.ToLower(params["key"])
+		for _, value := range s.state.Authorizations[key][name] {
+			if value == params["value"] {
+				writeJSON(w, map[string]bool{"success": true})
+				return
+			}
+		}
+		s.state.Authorizations[key][name] = append(s.state.Authorizations[key][name], params["value"])
+		writeJSON(w, map[string]bool{"success": true})
+	})
+
+	// removes an attribute value from a user or group, https://documentation.immuta.com/SaaS/policy-as-code/v1-api/configure/bim/#remove-a-user-or-groups-attribute
+	s.handle(http.MethodDelete, "/bim/iam/{iamId}/{modelType}/{modelId}/authorizations/{key}/{value}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		authorizations := s.state.Authorizations[AuthorizationsKey(params["iamId"], params["modelType"], params["modelId"])]
+		name := strings.ToLower(params["key"])
+		for i, value := range authorizations[name] {
+			if value == params["value"] {
+				authorizations[name] = append(authorizations[name][:i:i], authorizations[name][i+1:]...)
+				if len(authorizations[name]) == 0 {
+					delete(authorizations, name)
+				}
+				writeJSON(w, map[string]bool{"success": true})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Authorization not found")
+	})
+}
+
+// modelExists reports whether the user or group attributes are attached to exists
+func (s *Server) modelExists(iamId, modelType, modelId string) bool {
+	switch modelType {
+	case "user":
+		user, ok := s.state.Users[modelId]
+		return ok && user.Iamid == iamId
+	case "group":
+		id, err := strconv.Atoi(modelId)
+		if err != nil {
+			return false
+		}
+		group, ok := s.state.Groups[id]
+		return ok && group.IamId == iamId
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package fakeimmuta
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+func (s *Server) routeDataSources() {
+	// registers a connection, also as a dry run. V2 data source API: no public reference could be confirmed, the shape follows client.DataSourceInput and client.DataSourceResponse
+	s.handle(http.MethodPost, "/api/v2/data", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		input := client.DataSourceInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+		if input.ConnectionKey == "" {
+			writeError(w, http.StatusBadRequest, "connectionKey is required")
+			return
+		}
+		if input.Connection.Handler == "" || input.Connection.Hostname == "" {
+			writeError(w, http.StatusBadRequest, "connection.handler and connection.hostname are required")
+			return
+		}
+
+		response := client.DataSourceResponse{
+			DryRun:   r.URL.Query().Get("dryRun") == "true",
+			Creating: []string{},
+			Updating: []string{},
+			Deleting: []string{},
+			NoChange: []string{},
+		}
+		// without sources the fake registers the connection as a single data source named after its key
+		names := []string{input.ConnectionKey}
+		if len(input.Sources) > 0 {
+			tables, err := s.selectTables(input)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			names = tables
+		}
+
+		existing, ok := s.state.DataSources[input.ConnectionKey]
+		switch {
+		case !ok:
+			response.Creating = append(response.Creating, names...)
+		case reflect.DeepEqual(*existing, input):
+			response.NoChange = append(response.NoChange, names...)
+		default:
+			response.Updating = append(response.Updating, names...)
+		}
+
+		response.DetectionRunning = s.state.DetectionPolls[input.ConnectionKey] > 0
+		if !response.DryRun {
+			s.state.DataSources[input.ConnectionKey] = &input
+		} else if response.DetectionRunning {
+			s.state.DetectionPolls[input.ConnectionKey]--
+		}
+		writeJSON(w, response)
+	})
+
+	// reads a registered connection without its secrets. V2 data source API: no public reference could be confirmed, the shape follows client.DataSourceInput
+	s.handle(http.MethodGet, "/api/v2/data/{connectionKey}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		existing, ok := s.state.DataSources[params["connectionKey"]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Connection not found")
+			return
+		}
+
+		// like Immuta, never hand the secrets back
+		dataSource := *existing
+		dataSource.Connection.Password = ""
+		dataSource.Connection.UserFiles = nil
+		for _, file := range existing.Connection.UserFiles {
+			file.Content = ""
+			dataSource.Connection.UserFiles = append(dataSource.Connection.UserFiles, file)
+		}
+		writeJSON(w, dataSource)
+	})
+
+	// deletes a connection and its data sources. V2 data source API: no public reference could be confirmed, the shape follows client.DataSourceResponse
+	s.handle(http.MethodDelete, "/api/v2/data/{connectionKey}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if _, ok := s.state.DataSources[params["connectionKey"]]; !ok {
+			writeError(w, http.StatusNotFound, "Connection not found")
+			return
+		}
+
+		response := client.DataSourceResponse{DryRun: r.URL.Query().Get("dryRun") == "true", Deleting: []string{params["connectionKey"]}}
+		if !response.DryRun {
+			delete(s.state.DataSources, params["connectionKey"])
+		}
+		writeJSON(w, response)
+	})
+}
+
+// selectTables returns the tables of the catalog selected by the sources of a registration, as "SCHEMA.TABLE"
+func (s *Server) selectTables(input client.DataSourceInput) ([]string, error) {
+	selected := map[string]bool{}
+	for _, source := range input.Sources {
+		schema := source.Schema
+		if schema == "" {
+			schema = input.Connection.Schema
+		}
+
+		name := schema + "." + source.Table
+		if !contains(s.state.Catalog, name) {
+			return nil, fmt.Errorf("table %s does not exist", name)
+		}
+		selected[name] = true
+	}
+
+	tables := make([]string, 0, len(selected))
+	for name := range selected {
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package fakeimmuta
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+func (s *Server) routeProjects() {
+	// searches projects with hits and count, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodGet, "/project", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		searchText := strings.ToLower(r.URL.Query().Get("searchText"))
+		projects := make([]client.Project, 0, len(s.state.Projects))
+		for _, id := range sortedIds(s.state.Projects) {
+			if strings.Contains(strings.ToLower(s.state.Projects[id].Name), searchText) {
+				projects = append(projects, *s.state.Projects[id])
+			}
+		}
+		writeJSON(w, client.FindProjectsResponse{Hits: paginate(r, projects), Count: len(projects)})
+	})
+
+	// reads a project, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodGet, "/project/{id}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		id, ok := intParam(w, params, "id")
+		if !ok {
+			return
+		}
+		project, ok := s.state.Projects[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		writeJSON(w, project)
+	})
+
+	// acknowledges the purposes of a project for a member, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodPost, "/project/{id}/members/{memberId}/acknowledge", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		id, ok := intParam(w, params, "id")
+		if !ok {
+			return
+		}
+		if _, ok := s.state.Projects[id]; !ok {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		s.state.Acknowledged[id] = true
+		writeJSON(w, map[string]bool{"acknowledged": true})
+	})
+
+	// creates or updates a project by key. V2 project API: no public reference could be confirmed, the shape follows client.Project
+	s.handle(http.MethodPost, "/api/v2/project", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		input := client.ProjectInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+		if input.Name == "" || input.ProjectKey == "" {
+			writeError(w, http.StatusBadRequest, "name and projectKey are required")
+			return
+		}
+
+		purposes := make([]client.Purpose, 0, len(input.Purposes))
+		for _, name := range input.Purposes {
+			purpose := s.findPurpose(name)
+			if purpose == nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Purpose %q does not exist", name))
+				return
+			}
+			purposes = append(purposes, purpose.Purpose)
+		}
+		tags := make([]client.Tag, 0, len(input.Tags))
+		for _, name := range input.Tags {
+			tag := s.findTag(name)
+			if tag == nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Tag %q does not exist", name))
+				return
+			}
+			tags = append(tags, client.Tag{Id: tag.Id, Name: tag.Name, DisplayName: tag.DisplayName, Source: tag.Source})
+		}
+
+		response := client.ProjectResourceResponseV2{DryRun: r.URL.Query().Get("dryRun") == "true"}
+		project := s.findProject(input.ProjectKey)
+		if project == nil {
+			response.Creating = true
+			project = &client.Project{Id: s.state.NextId(), SubscriptionId: s.state.NextId(), Status: "open", CreatedAt: time.Now()}
+		} else {
+			response.Updating = true
+		}
+		response.ProjectId = project.Id
+
+		if response.DryRun {
+			writeJSON(w, response)
+			return
+		}
+
+		project.ProjectInput = input
+		project.Purposes = purposes
+		project.Tags = tags
+		project.UpdateAt = time.Now()
+		s.state.Projects[project.Id] = project
+
+		// like Immuta, the project is saved but the request fails until its purposes are acknowledged
+		for _, purpose := range purposes {
+			if purpose.Acknowledgement != "" && !s.state.Acknowledged[project.Id] {
+				writeError(w, http.StatusBadRequest, "You must first acknowledge the purposes of the project")
+				return
+			}
+		}
+		writeJSON(w, response)
+	})
+
+	// deletes a project by key. V2 project API: no public reference could be confirmed, the shape follows client.ProjectsService
+	s.handle(http.MethodDelete, "/api/v2/project/{projectKey}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		project := s.findProject(params["projectKey"])
+		if project == nil {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		delete(s.state.Projects, project.Id)
+		delete(s.state.Acknowledged, project.Id)
+		writeJSON(w, client.ProjectResourceResponseV2{ProjectId: project.Id})
+	})
+}
+
+func (s *Server) findProject(projectKey string) *client.Project {
+	for _, project := range s.state.Projects {
+		if project.ProjectKey == projectKey {
+			return project
+		}
+	}
+	return nil
+}
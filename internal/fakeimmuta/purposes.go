@@ -0,0 +1,86 @@
+package fakeimmuta
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+func (s *Server) routePurposes() {
+	// lists purposes, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodGet, "/governance/purpose", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		purposes := make([]client.PurposeResponse, 0, len(s.state.Purposes))
+		for _, id := range sortedIds(s.state.Purposes) {
+			purposes = append(purposes, *s.state.Purposes[id])
+		}
+		writeJSON(w, client.Purposes{Purposes: paginate(r, purposes), Count: len(purposes)})
+	})
+
+	// reads a purpose, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodGet, "/governance/purpose/{id}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		id, ok := intParam(w, params, "id")
+		if !ok {
+			return
+		}
+		purpose, ok := s.state.Purposes[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Purpose not found")
+			return
+		}
+		writeJSON(w, purpose)
+	})
+
+	// deletes a purpose, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodDelete, "/governance/purpose/{id}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		id, ok := intParam(w, params, "id")
+		if !ok {
+			return
+		}
+		if _, ok := s.state.Purposes[id]; !ok {
+			writeError(w, http.StatusNotFound, "Purpose not found")
+			return
+		}
+		delete(s.state.Purposes, id)
+		writeJSON(w, map[string]int{"id": id})
+	})
+
+	// creates or updates a purpose by name. V2 purpose API: no public reference could be confirmed, the shape follows client.PurposeResponse
+	s.handle(http.MethodPost, "/api/v2/purpose", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		input := client.PurposeInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+		if input.Name == "" {
+			writeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+
+		response := client.PurposeResourceResponseV2{DryRun: r.URL.Query().Get("dryRun") == "true"}
+		purpose := s.findPurpose(input.Name)
+		if purpose == nil {
+			response.Creating = true
+			purpose = &client.PurposeResponse{Id: s.state.NextId(), CreatedAt: time.Now()}
+		} else {
+			response.Updating = true
+		}
+		response.PurposeId = purpose.Id
+
+		if !response.DryRun {
+			purpose.PurposeInput = input
+			purpose.DisplayAcknowledgement = input.Acknowledgement != ""
+			purpose.UpdatedAt = time.Now()
+			s.state.Purposes[purpose.Id] = purpose
+		}
+		writeJSON(w, response)
+	})
+}
+
+func (s *Server) findPurpose(name string) *client.PurposeResponse {
+	for _, purpose := range s.state.Purposes {
+		if purpose.Name == name {
+			return purpose
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+// Package fakeimmuta is an in-process, stateful stand-in for the parts of the Immuta API used by the provider,
+// so resources can be tested without a tenant or network access.
+//
+// Each route names the Immuta API reference it models. V1 routes link the pages this repository already cites, the
+// V1 API introduction where an endpoint has no page of its own. No public reference could be confirmed for the V2
+// routes and /version, they model the payloads of the client package instead and may differ from a real tenant.
+package fakeimmuta
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+const (
+	// DefaultToken is the only bearer token accepted by a new Server
+	DefaultToken = "fake-immuta-token"
+	// DefaultRelease is reported by /version, new enough for every V2 endpoint
+	DefaultRelease = "2023.2.0"
+)
+
+// State is everything the fake tenant knows about, tests can change it through Server.Mutate to simulate drift
+type State struct {
+	Release     string
+	Purposes    map[int]*client.PurposeResponse
+	Projects    map[int]*client.Project
+	Tags        map[int]*client.TagList
+	Users       map[string]*client.BimUser
+	Groups      map[int]*client.BimGroup
+	GroupUsers  map[int][]client.BimGroupUser
+	DataSources map[string]*client.DataSourceInput
+	// DetectionPolls is how many more dry run registrations report a connection's detection as running, each dry
+	// run counts one down so tests can simulate a slow detection
+	DetectionPolls map[string]int
+	// Catalog lists the tables of the data platform behind every connection as "SCHEMA.TABLE", registrations
+	// with sources select from it
+	Catalog []string
+	// Acknowledged records the projects whose purposes have been acknowledged
+	Acknowledged map[int]bool
+	// Authorizations holds the attributes of users and groups, keyed by AuthorizationsKey
+	Authorizations map[string]map[string][]string
+	// Unserved lists routes, as "METHOD /path/{param}", answered with a 405 as if the tenant did not have them
+	Unserved map[string]bool
+
+	lastId int
+}
+
+// NextId hands out ids shared by every kind of object, like a database sequence
+func (state *State) NextId() int {
+	state.lastId++
+	return state.lastId
+}
+
+// AuthorizationsKey identifies the user or group attributes belong to
+func AuthorizationsKey(iamId, modelType, modelId string) string {
+	return fmt.Sprintf("%s/%s/%s", iamId, modelType, modelId)
+}
+
+// Server is a TLS test server holding the State of a fake tenant
+type Server struct {
+	*httptest.Server
+	// Token is the bearer token requests must carry
+	Token string
+
+	mu     sync.Mutex
+	state  State
+	routes []route
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// NewServer starts a fake tenant with no objects, it must be closed once the test is done
+func NewServer() *Server {
+	s := &Server{
+		Token: DefaultToken,
+		state: State{
+			Release:        DefaultRelease,
+			Purposes:       map[int]*client.PurposeResponse{},
+			Projects:       map[int]*client.Project{},
+			Tags:           map[int]*client.TagList{},
+			Users:          map[string]*client.BimUser{},
+			Groups:         map[int]*client.BimGroup{},
+			GroupUsers:     map[int][]client.BimGroupUser{},
+			DataSources:    map[string]*client.DataSourceInput{},
+			DetectionPolls: map[string]int{},
+			Acknowledged:   map[int]bool{},
+			Authorizations: map[string]map[string][]string{},
+			Unserved:       map[string]bool{},
+		},
+	}
+
+	s.routeMeta()
+	s.routePurposes()
+	s.routeProjects()
+	s.routeTags()
+	s.routeBim()
+	s.routeDataSources()
+
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+// Host is the host and port to configure the provider with
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// Client returns a client trusting the server's certificate and authenticated with its token
+func (s *Server) Client(opts ...client.Option) *client.ImmutaClient {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+
+	defaults := []client.Option{
+		client.WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		client.WithRetryConfig(client.RetryConfig{}),
+	}
+
+	return client.NewClient(s.Host(), s.Token, "fakeimmuta", append(defaults, opts...)...)
+}
+
+// Mutate changes the state of the tenant, e.g. to simulate changes made outside Terraform
+func (s *Server) Mutate(f func(state *State)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(&s.state)
+}
+
+func (s *Server) handle(method, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+// unauthenticated paths are reachable without a bearer token
+var unauthenticated = map[string]bool{
+	"/version":                 true,
+	"/bim/apikey/authenticate": true,
+	"/bim/login":               true,
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !unauthenticated[r.URL.Path] && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, route := range s.routes {
+		if route.method != r.Method {
+			continue
+		}
+		if params, ok := route.match(segments); ok {
+			if s.state.Unserved[route.method+" /"+strings.Join(route.segments, "/")] {
+				writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s %s is not served", r.Method, r.URL.Path))
+				return
+			}
+			route.handler(w, r, params)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s is not implemented by the fake", r.Method, r.URL.Path))
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[strings.Trim(segment, "{}")] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) routeMeta() {
+	// reports the release of the tenant. This endpoint is not in Immuta's published API reference, the shape
+	// follows client.DetectRelease, which treats a tenant that does not answer it as supporting every feature.
+	s.handle(http.MethodGet, "/version", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		writeJSON(w, map[string]string{"version": s.state.Release})
+	})
+
+	// reads the authenticated user, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodGet, "/bim/rpc/user/current", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		writeJSON(w, client.CurrentUser{Id: 1, UserId: "admin@fake.immuta", Name: "Fake Admin"})
+	})
+
+	// exchanges an API key or credentials for a bearer token, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	issueToken := func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		writeJSON(w, map[string]string{"token": s.Token})
+	}
+	s.handle(http.MethodPost, "/bim/apikey/authenticate", issueToken)
+	s.handle(http.MethodPost, "/bim/login", issueToken)
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError responds with Immuta's JSON error envelope
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"statusCode": status,
+		"error":      http.StatusText(status),
+		"message":    message,
+	})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, into interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(into); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+		return false
+	}
+	return true
+}
+
+func intParam(w http.ResponseWriter, params map[string]string, name string) (int, bool) {
+	id, err := strconv.Atoi(params[name])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("%s must be a number", name))
+		return 0, false
+	}
+	return id, true
+}
+
+// paginate applies the offset and size query parameters to a list
+func paginate[T any](r *http.Request, items []T) []T {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	size, err := strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil || size <= 0 {
+		size = len(items)
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + size
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// sortedIds returns the keys of a map in ascending order so lists are stable
+func sortedIds[T any](m map[int]T) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,80 @@
+package fakeimmuta
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+func TestServer_requiresToken(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	c := server.Client(client.WithTokenSource(client.StaticToken("wrong")))
+	if _, err := c.Validate(context.Background()); !client.IsUnauthorized(err) {
+		t.Errorf("expected a 401 with the wrong token, got %v", err)
+	}
+
+	c = server.Client(client.WithAPIKey("any key"))
+	if _, err := c.Validate(context.Background()); err != nil {
+		t.Errorf("expected the exchanged token to be accepted, got %s", err)
+	}
+}
+
+func TestServer_paginatesLists(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.Mutate(func(state *State) {
+		for i := 0; i < 250; i++ {
+			user := &client.BimUser{Userid: fmt.Sprintf("user%03d", i), Iamid: client.DefaultIamId}
+			state.Users[user.Userid] = user
+		}
+	})
+
+	users, err := server.Client().BimUsers().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users.Count != 250 || users.Users[249].Userid != "user249" {
+		t.Errorf("expected all 250 users in order, got %d", users.Count)
+	}
+}
+
+func TestServer_upsertIsKeyedByName(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	c := server.Client()
+	ctx := context.Background()
+
+	created, err := c.Purposes().Upsert(ctx, client.PurposeInput{Purpose: client.Purpose{Name: "Research"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := c.Purposes().Upsert(ctx, client.PurposeInput{Purpose: client.Purpose{Name: "Research", Description: "updated"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Creating || !updated.Updating || created.PurposeId != updated.PurposeId {
+		t.Errorf("expected the second upsert to update the first purpose, got %+v and %+v", created, updated)
+	}
+
+	if err := c.Purposes().Delete(ctx, fmt.Sprint(created.PurposeId)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Purposes().Get(ctx, fmt.Sprint(created.PurposeId)); !client.IsNotFound(err) {
+		t.Errorf("expected a 404 for a deleted purpose, got %v", err)
+	}
+}
+
+func TestServer_unknownPath(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	err := server.Client().GetContext(context.Background(), "/not/implemented", "", nil, nil)
+	if !client.IsNotFound(err) {
+		t.Errorf("expected a 404 for an unknown path, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package fakeimmuta
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/immuta/terraform-provider-immuta/client"
+)
+
+func (s *Server) routeTags() {
+	// creates tags, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodPost, "/tag", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		input := client.TagInput{}
+		if !decode(w, r, &input) {
+			return
+		}
+
+		prefix := ""
+		if input.RootTag != nil && input.RootTag.Name != "" {
+			prefix = input.RootTag.Name + "."
+		}
+
+		created := make([]client.TagCreateResponse, 0, len(input.Tags))
+		for _, tag := range input.Tags {
+			name := tag.Name
+			if !strings.HasPrefix(name, prefix) {
+				name = prefix + name
+			}
+			if s.findTag(name) != nil {
+				continue
+			}
+			now := time.Now()
+			tagList := &client.TagList{Id: s.state.NextId(), Name: name, Source: "curated", DisplayName: name[strings.LastIndex(name, ".")+1:]}
+			s.state.Tags[tagList.Id] = tagList
+			created = append(created, client.TagCreateResponse{
+				Id: tagList.Id, Name: name, Source: tagList.Source, CreatedBy: 1, CreatedAt: now, UpdatedAt: now,
+			})
+		}
+		writeJSON(w, created)
+	})
+
+	// searches tags as a bare array, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodGet, "/tag", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		searchText := strings.ToLower(r.URL.Query().Get("searchText"))
+		tags := make([]client.TagList, 0)
+		for _, id := range sortedIds(s.state.Tags) {
+			if strings.Contains(strings.ToLower(s.state.Tags[id].Name), searchText) {
+				tags = append(tags, *s.state.Tags[id])
+			}
+		}
+		writeJSON(w, paginate(r, tags))
+	})
+
+	// deletes a tag, https://documentation.immuta.com/saas/developer-guides/api-intro/immuta-v1-api
+	s.handle(http.MethodDelete, "/tag/{name}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		tag := s.findTag(params["name"])
+		if tag == nil {
+			writeError(w, http.StatusNotFound, "Tag not found")
+			return
+		}
+		// deleting a tag deletes its children too
+		for id, child := range s.state.Tags {
+			if child.Name == tag.Name || strings.HasPrefix(child.Name, tag.Name+".") {
+				delete(s.state.Tags, id)
+			}
+		}
+		writeJSON(w, []client.TagList{*tag})
+	})
+}
+
+func (s *Server) findTag(name string) *client.TagList {
+	for _, tag := range s.state.Tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package tracing sets up OpenTelemetry tracing of the provider from the standard OTEL_* environment variables.
+// Tracing is off unless OTEL_TRACES_EXPORTER names an exporter, the global no-op tracer is used then.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	serviceName = "terraform-provider-immuta"
+
+	exporterOTLP    = "otlp"
+	exporterConsole = "console"
+	exporterNone    = "none"
+)
+
+// Setup installs a global tracer provider when tracing is enabled through the environment.
+// The returned function flushes any buffered spans and must be called before the provider exits.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	exporters := exportersFromEnv(os.Getenv)
+	if len(exporters) == 0 {
+		return noop, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(version)),
+		resource.WithTelemetrySDK(),
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults above
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("could not describe the tracing resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	for _, name := range exporters {
+		exporter, err := newExporter(ctx, name)
+		if err != nil {
+			return noop, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case exporterOTLP:
+		// sends spans with OTLP over HTTP, the exporter reads its endpoint, headers and timeout from the
+		// OTEL_EXPORTER_OTLP_* variables itself
+		return otlptracehttp.New(ctx)
+	case exporterConsole:
+		// stdout carries the plugin handshake, so spans are written to stderr which ends up in the Terraform logs
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	}
+	return nil, fmt.Errorf("OTEL_TRACES_EXPORTER %q is not supported, use %q, %q or %q", name, exporterOTLP, exporterConsole, exporterNone)
+}
+
+// exportersFromEnv reads the exporters listed in OTEL_TRACES_EXPORTER, tracing is off when there are none
+func exportersFromEnv(getenv func(string) string) []string {
+	var exporters []string
+	for _, name := range strings.Split(getenv("OTEL_TRACES_EXPORTER"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == exporterNone {
+			continue
+		}
+		exporters = append(exporters, name)
+	}
+	return exporters
+}
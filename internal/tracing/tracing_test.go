@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestExportersFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		env       map[string]string
+		exporters []string
+	}{
+		"off by default": {
+			env: map[string]string{},
+		},
+		"an endpoint alone does not enable tracing": {
+			env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+		},
+		"exporter none": {
+			env: map[string]string{"OTEL_TRACES_EXPORTER": "none"},
+		},
+		"otlp and console": {
+			env:       map[string]string{"OTEL_TRACES_EXPORTER": "otlp, console"},
+			exporters: []string{"otlp", "console"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			exporters := exportersFromEnv(func(key string) string { return test.env[key] })
+			if !reflect.DeepEqual(exporters, test.exporters) {
+				t.Errorf("expected exporters %v, got %v", test.exporters, exporters)
+			}
+		})
+	}
+}
+
+func TestOTLPExporter_sendsProtobuf(t *testing.T) {
+	received := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" || r.Header.Get("x-tenant") != "a" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- body
+	}))
+	defer server.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-tenant=a")
+	exporter, err := newExporter(context.Background(), exporterOTLP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() {
+		_ = provider.Shutdown(context.Background())
+	}()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "immuta_tag.create")
+	_, child := provider.Tracer("test").Start(ctx, "HTTP POST")
+	child.End()
+
+	// the protobuf encoding keeps the span name and the raw parent span id as they are
+	body := <-received
+	parentID := parent.SpanContext().SpanID()
+	if !bytes.Contains(body, []byte("HTTP POST")) || !bytes.Contains(body, parentID[:]) {
+		t.Errorf("expected the child span with its parent in the export, got %x", body)
+	}
+	parent.End()
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/immuta/terraform-provider-immuta/immuta"
+	"github.com/immuta/terraform-provider-immuta/internal/tracing"
 	"log"
 )
 
@@ -28,7 +29,19 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), immuta.NewProvider(version), opts)
+	ctx := context.Background()
+
+	// tracing is only enabled through the OTEL_* environment variables
+	shutdownTracing, err := tracing.Setup(ctx, version)
+	if err != nil {
+		log.Printf("[WARN] OpenTelemetry tracing is disabled: %s", err)
+	}
+
+	err = providerserver.Serve(ctx, immuta.NewProvider(version), opts)
+
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		log.Printf("[WARN] could not flush OpenTelemetry spans: %s", shutdownErr)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())